@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mux"
+)
+
+// OpenAPI writes the OpenAPI document of the web API to a file, or to stdout when no path is given
+func OpenAPI(path string) error {
+	doc := mux.OpenAPI(mux.Config{})
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding document: %w", err)
+	}
+	data := buf.Bytes()
+
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating document directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing document: %w", err)
+	}
+
+	fmt.Println("Wrote OpenAPI document: path = ", path)
+
+	return nil
+}
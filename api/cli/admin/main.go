@@ -65,6 +65,10 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		if err := commands.GenKey(cfg.Auth.KeysFolder); err != nil {
 			return fmt.Errorf("generating key: %w", err)
 		}
+	case "openapi":
+		if err := commands.OpenAPI(args.Num(1)); err != nil {
+			return fmt.Errorf("generating openapi document: %w", err)
+		}
 	}
 	return nil
 }
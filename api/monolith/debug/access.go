@@ -0,0 +1,170 @@
+package debug
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+// DefaultRole is the role a token must carry to use the debug server when none is configured
+const DefaultRole = "admin"
+
+// AccessConfig represents the optional protection of the debug server
+// AllowedNetworks holds IP addresses or CIDR ranges and an empty list allows every address
+// When Token or Auth is set a request must present the static token or a JWT carrying Role as a bearer token
+type AccessConfig struct {
+	AllowedNetworks []string
+	Token           string
+	Auth            *auth.Auth
+	Role            string
+}
+
+// Protected checks if the configuration restricts who can use the debug server
+func (cfg AccessConfig) Protected() bool {
+	return len(cfg.AllowedNetworks) > 0 || cfg.Token != "" || cfg.Auth != nil
+}
+
+// access checks every debug request against the allowed networks and credentials
+type access struct {
+	log      *logger.Logger
+	networks []netip.Prefix
+	token    string
+	auth     *auth.Auth
+	role     string
+}
+
+// newAccess parses the allowed networks of the configuration
+func newAccess(log *logger.Logger, cfg AccessConfig) (*access, error) {
+	a := access{
+		log:   log,
+		token: cfg.Token,
+		auth:  cfg.Auth,
+		role:  cfg.Role,
+	}
+
+	if a.role == "" {
+		a.role = DefaultRole
+	}
+
+	for _, n := range cfg.AllowedNetworks {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+
+		if !strings.Contains(n, "/") {
+			addr, err := netip.ParseAddr(n)
+			if err != nil {
+				return nil, fmt.Errorf("parsing allowed address %q: %w", n, err)
+			}
+			a.networks = append(a.networks, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, fmt.Errorf("parsing allowed network %q: %w", n, err)
+		}
+		a.networks = append(a.networks, prefix.Masked())
+	}
+
+	return &a, nil
+}
+
+// wrap rejects requests from addresses that are not allowed or without valid credentials
+func (a *access) wrap(handler http.Handler) http.Handler {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if !a.allowedAddr(r.RemoteAddr) {
+			a.deny(w, r, http.StatusForbidden, "address not allowed")
+			return
+		}
+
+		if status, reason := a.authorize(r); status != http.StatusOK {
+			a.deny(w, r, status, reason)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(h)
+}
+
+// allowedAddr checks if the remote address of a request is in one of the allowed networks
+func (a *access) allowedAddr(remoteAddr string) bool {
+	if len(a.networks) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, n := range a.networks {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorize checks the bearer token of a request against the static token and the JWT role
+// It returns the status to respond with and the reason a request is rejected
+func (a *access) authorize(r *http.Request) (int, string) {
+	if a.token == "" && a.auth == nil {
+		return http.StatusOK, ""
+	}
+
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return http.StatusUnauthorized, "missing bearer token"
+	}
+
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+		return http.StatusOK, ""
+	}
+
+	if a.auth == nil {
+		return http.StatusUnauthorized, "invalid token"
+	}
+
+	ctx := r.Context()
+
+	claims, err := a.auth.Authenticate(ctx, "Bearer "+token)
+	if err != nil {
+		return http.StatusUnauthorized, "invalid token"
+	}
+
+	if err := a.auth.Authorize(ctx, claims, a.role); err != nil {
+		return http.StatusForbidden, fmt.Sprintf("subject [%s] lacks role [%s]", claims.Subject, a.role)
+	}
+
+	return http.StatusOK, ""
+}
+
+// deny logs the rejected request and responds with the status
+func (a *access) deny(w http.ResponseWriter, r *http.Request, status int, reason string) {
+	if a.log != nil {
+		a.log.Warn(r.Context(), "debug access denied", "method", r.Method, "path", r.URL.Path, "remoteAddr", r.RemoteAddr, "reason", reason)
+	}
+
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="debug"`)
+	}
+
+	http.Error(w, http.StatusText(status), status)
+}
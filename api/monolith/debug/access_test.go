@@ -0,0 +1,111 @@
+package debug_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/debug"
+	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyStore serves a single RSA key pair generated for the test
+type keyStore struct {
+	private string
+	public  string
+}
+
+func (ks keyStore) PrivateKey(kid string) (string, error) { return ks.private, nil }
+func (ks keyStore) PublicKey(kid string) (string, error)  { return ks.public, nil }
+
+func Test_Access(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Should generate a key: %s", err)
+	}
+
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	publicDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+
+	ks := keyStore{
+		private: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		public:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+	}
+
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", nil)
+
+	a, err := auth.New(auth.Config{Log: log, KeyLookup: ks, Issuer: "critiquefy"})
+	if err != nil {
+		t.Fatalf("Should construct auth: %s", err)
+	}
+
+	token := func(roles ...string) string {
+		claims := auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "critiquefy",
+				Subject:   "c11eabcc-8492-4dfa-a586-97d9f1694a8a",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+			Roles: roles,
+		}
+
+		tkn, err := a.GenerateToken("kid", claims)
+		if err != nil {
+			t.Fatalf("Should generate a token: %s", err)
+		}
+		return tkn
+	}
+
+	cases := []struct {
+		name          string
+		access        debug.AccessConfig
+		authorization string
+		expected      int
+	}{
+		{name: "Success_Open", access: debug.AccessConfig{}, expected: http.StatusOK},
+		{name: "Fail_MissingToken", access: debug.AccessConfig{Token: "s3cret"}, expected: http.StatusUnauthorized},
+		{name: "Fail_WrongToken", access: debug.AccessConfig{Token: "s3cret"}, authorization: "Bearer guess", expected: http.StatusUnauthorized},
+		{name: "Success_StaticToken", access: debug.AccessConfig{Token: "s3cret"}, authorization: "Bearer s3cret", expected: http.StatusOK},
+		{name: "Success_AdminJWT", access: debug.AccessConfig{Auth: a}, authorization: "Bearer " + token("admin"), expected: http.StatusOK},
+		{name: "Fail_UserJWT", access: debug.AccessConfig{Auth: a}, authorization: "Bearer " + token("user"), expected: http.StatusForbidden},
+		{name: "Fail_AddressNotAllowed", access: debug.AccessConfig{AllowedNetworks: []string{"10.0.0.0/8", "127.0.0.1"}}, expected: http.StatusForbidden},
+		{name: "Success_AddressAllowed", access: debug.AccessConfig{AllowedNetworks: []string{"192.0.2.0/24"}}, expected: http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, err := debug.Mux(debug.Config{Log: log, Access: c.access})
+			if err != nil {
+				t.Fatalf("Should construct the debug router: %s", err)
+			}
+
+			// httptest requests come from 192.0.2.1
+			r := httptest.NewRequest(http.MethodGet, "/debug/loglevel", nil)
+			if c.authorization != "" {
+				r.Header.Set("Authorization", c.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != c.expected {
+				t.Errorf("Should respond %d, got %d", c.expected, w.Code)
+			}
+		})
+	}
+
+	t.Run("Fail_InvalidNetwork", func(t *testing.T) {
+		if _, err := debug.Mux(debug.Config{Log: log, Access: debug.AccessConfig{AllowedNetworks: []string{"10.0.0.0/33"}}}); err == nil {
+			t.Errorf("Should reject an invalid network")
+		}
+	})
+}
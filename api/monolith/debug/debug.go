@@ -2,13 +2,39 @@ package debug
 
 import (
 	"expvar"
+	"fmt"
 	"net/http"
 	"net/http/pprof"
 
+	"github.com/andrew-hayworth22/critiquefy-service/app/metrics"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/buildinfo"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/health"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
 	"github.com/arl/statsviz"
 )
 
-func Mux() *http.ServeMux {
+// Config contains the dependencies needed to construct the debug server
+// Logs and Health are optional and their routes are left out when nil
+// Config is the effective configuration as printed by conf.String, with sensitive values masked
+// Access protects every route and leaves the server open when empty
+type Config struct {
+	Log       *logger.Logger
+	Logs      *logger.Buffer
+	Health    *health.Registry
+	Info      buildinfo.Info
+	ActiveKID string
+	Config    string
+	Access    AccessConfig
+}
+
+// Mux constructs the debug server routes for profiling, metrics, runtime log control, recent logs, health and build info
+// The routes are wrapped by the access checks of the configuration
+func Mux(cfg Config) (http.Handler, error) {
+	access, err := newAccess(cfg.Log, cfg.Access)
+	if err != nil {
+		return nil, fmt.Errorf("constructing debug access: %w", err)
+	}
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -17,8 +43,27 @@ func Mux() *http.ServeMux {
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	mux.Handle("/debug/vars/", expvar.Handler())
+	mux.Handle("/metrics", metrics.Handler())
+
+	ll := logLevelHandlers{log: cfg.Log}
+	mux.HandleFunc("GET /debug/loglevel", ll.get)
+	mux.HandleFunc("PUT /debug/loglevel", ll.put)
+
+	ih := newInfoHandlers(cfg.Info, cfg.ActiveKID, cfg.Config)
+	mux.HandleFunc("GET /debug/info", ih.get)
+
+	if cfg.Logs != nil {
+		lh := logsHandlers{buf: cfg.Logs}
+		mux.HandleFunc("GET /debug/logs", lh.list)
+		mux.HandleFunc("GET /debug/logs/tail", lh.tail)
+	}
+
+	if cfg.Health != nil {
+		hh := healthHandlers{checks: cfg.Health}
+		mux.HandleFunc("GET /debug/health", hh.get)
+	}
 
 	statsviz.Register(mux)
 
-	return mux
+	return access.wrap(mux), nil
 }
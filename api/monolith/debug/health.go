@@ -0,0 +1,59 @@
+package debug
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/health"
+)
+
+// healthReport represents the detailed health of the service reported by the debug server
+type healthReport struct {
+	Status     string            `json:"status"`
+	Components []healthComponent `json:"components"`
+}
+
+// healthComponent represents the detailed health of one dependency including its last error
+type healthComponent struct {
+	Name                string    `json:"name"`
+	Status              string    `json:"status"`
+	Critical            bool      `json:"critical"`
+	Timeout             string    `json:"timeout"`
+	LatencyMS           float64   `json:"latency_ms"`
+	Error               string    `json:"error,omitempty"`
+	CheckedAt           time.Time `json:"checked_at"`
+	LastSuccess         time.Time `json:"last_success,omitzero"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
+// healthHandlers serves the detailed health of the service
+type healthHandlers struct {
+	checks *health.Registry
+}
+
+// get runs the health checks and reports every detail of the results
+func (h healthHandlers) get(w http.ResponseWriter, r *http.Request) {
+	report := h.checks.Run(r.Context())
+
+	components := make([]healthComponent, len(report.Components))
+	for i, c := range report.Components {
+		components[i] = healthComponent{
+			Name:                c.Name,
+			Status:              c.Status,
+			Critical:            c.Critical,
+			Timeout:             c.Timeout.String(),
+			LatencyMS:           float64(c.Latency.Microseconds()) / 1000,
+			Error:               c.Error,
+			CheckedAt:           c.CheckedAt,
+			LastSuccess:         c.LastSuccess,
+			ConsecutiveFailures: c.ConsecutiveFailures,
+		}
+	}
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, healthReport{Status: report.Status, Components: components}, status)
+}
@@ -0,0 +1,75 @@
+package debug
+
+import (
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/buildinfo"
+)
+
+// info represents the build, runtime and configuration of the service reported by the debug server
+type info struct {
+	Build        string            `json:"build"`
+	Version      string            `json:"version"`
+	GoVersion    string            `json:"go_version"`
+	Revision     string            `json:"revision,omitempty"`
+	RevisionTime string            `json:"revision_time,omitempty"`
+	Modified     bool              `json:"modified"`
+	StartTime    time.Time         `json:"start_time"`
+	Uptime       string            `json:"uptime"`
+	GOMAXPROCS   int               `json:"gomaxprocs"`
+	Host         string            `json:"host"`
+	ActiveKID    string            `json:"active_kid"`
+	Config       map[string]string `json:"config"`
+}
+
+// infoHandlers serves the build, runtime and configuration of the service
+type infoHandlers struct {
+	info      buildinfo.Info
+	activeKID string
+	config    map[string]string
+}
+
+// newInfoHandlers constructs the handlers, parsing the configuration printed as one name=value per line
+func newInfoHandlers(bi buildinfo.Info, activeKID string, config string) infoHandlers {
+	settings := make(map[string]string)
+	for _, line := range strings.Split(config, "\n") {
+		if name, value, found := strings.Cut(line, "="); found {
+			settings[strings.TrimLeft(strings.TrimSpace(name), "-")] = value
+		}
+	}
+
+	return infoHandlers{
+		info:      bi,
+		activeKID: activeKID,
+		config:    settings,
+	}
+}
+
+// get reports the build, runtime and configuration of the service
+func (h infoHandlers) get(w http.ResponseWriter, r *http.Request) {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	data := info{
+		Build:        h.info.Build,
+		Version:      h.info.Version,
+		GoVersion:    h.info.GoVersion,
+		Revision:     h.info.Revision,
+		RevisionTime: h.info.RevisionTime,
+		Modified:     h.info.Modified,
+		StartTime:    h.info.StartTime,
+		Uptime:       h.info.Uptime().Round(time.Second).String(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		Host:         host,
+		ActiveKID:    h.activeKID,
+		Config:       h.config,
+	}
+
+	writeJSON(w, data, http.StatusOK)
+}
@@ -0,0 +1,112 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+// Bounds on how long a debug elevation lasts
+const (
+	defaultElevation = 15 * time.Minute
+	maxElevation     = 24 * time.Hour
+)
+
+// logLevel represents the logging state reported by the debug server
+type logLevel struct {
+	Level      string             `json:"level"`
+	Elevations []logger.Elevation `json:"elevations"`
+}
+
+// logLevelUpdate represents a change to the logging state
+// A scope and id elevate one trace or user to debug logging and a zero duration revokes the elevation
+// Otherwise the level replaces the minimum level of the service
+type logLevelUpdate struct {
+	Level    string `json:"level"`
+	Scope    string `json:"scope"`
+	ID       string `json:"id"`
+	Duration string `json:"duration"`
+}
+
+// logLevelHandlers serves the runtime log level of the service
+type logLevelHandlers struct {
+	log *logger.Logger
+}
+
+// get reports the minimum level and the active elevations
+func (h logLevelHandlers) get(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.state(), http.StatusOK)
+}
+
+// put changes the minimum level or elevates a trace or user and records the change as an audit entry
+func (h logLevelHandlers) put(w http.ResponseWriter, r *http.Request) {
+	var upd logLevelUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&upd); err != nil {
+		http.Error(w, fmt.Sprintf("decoding update: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	if upd.Scope == "" {
+		level, err := logger.ParseLevel(upd.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		previous := h.log.Level()
+		h.log.SetLevel(level)
+
+		h.log.Audit(ctx, "log level changed", "from", previous.String(), "to", level.String(), "remoteAddr", r.RemoteAddr)
+
+		writeJSON(w, h.state(), http.StatusOK)
+		return
+	}
+
+	d := defaultElevation
+	if upd.Duration != "" {
+		var err error
+		if d, err = time.ParseDuration(upd.Duration); err != nil || d < 0 {
+			http.Error(w, fmt.Sprintf("invalid duration %q", upd.Duration), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if d == 0 {
+		if h.log.Revoke(upd.Scope, upd.ID) {
+			h.log.Audit(ctx, "log level elevation revoked", "scope", upd.Scope, "id", upd.ID, "remoteAddr", r.RemoteAddr)
+		}
+
+		writeJSON(w, h.state(), http.StatusOK)
+		return
+	}
+
+	e, err := h.log.Elevate(upd.Scope, upd.ID, min(d, maxElevation))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.log.Audit(ctx, "log level elevated", "scope", e.Scope, "id", e.ID, "level", logger.LevelDebug.String(), "expires", e.Expires, "remoteAddr", r.RemoteAddr)
+
+	writeJSON(w, h.state(), http.StatusOK)
+}
+
+// state returns the current logging state
+func (h logLevelHandlers) state() logLevel {
+	return logLevel{
+		Level:      h.log.Level().String(),
+		Elevations: h.log.Elevations(),
+	}
+}
+
+// writeJSON responds with the value encoded as JSON
+func writeJSON(w http.ResponseWriter, v any, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
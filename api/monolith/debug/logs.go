@@ -0,0 +1,150 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// logRecord represents a buffered log record reported by the debug server
+type logRecord struct {
+	Seq        uint64         `json:"seq"`
+	Time       time.Time      `json:"time"`
+	Level      string         `json:"level"`
+	Message    string         `json:"msg"`
+	File       string         `json:"file"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// toLogRecord converts a buffered record into the debug server model
+func toLogRecord(r logger.BufferedRecord) logRecord {
+	return logRecord{
+		Seq:        r.Seq,
+		Time:       r.Time,
+		Level:      r.Level.String(),
+		Message:    r.Message,
+		File:       r.File,
+		Attributes: r.Attributes,
+	}
+}
+
+// logsHandlers serves the recent log records kept in memory
+type logsHandlers struct {
+	buf *logger.Buffer
+}
+
+// list returns the buffered records matching the level, trace_id and since query parameters
+func (h logsHandlers) list(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records := h.buf.Records(f)
+
+	list := make([]logRecord, len(records))
+	for i, rec := range records {
+		list[i] = toLogRecord(rec)
+	}
+
+	writeJSON(w, list, http.StatusOK)
+}
+
+// tail streams matching records as server-sent events as they are logged
+// A reconnecting client first receives the buffered records it missed
+func (h logsHandlers) tail(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub, cancel := h.buf.Subscribe()
+	defer cancel()
+
+	ctx, stop := context.WithCancel(r.Context())
+	defer stop()
+
+	es, err := web.NewEventStream(ctx, w, r)
+	if err != nil {
+		return
+	}
+
+	if lastID, err := strconv.ParseUint(es.LastEventID(), 10, 64); err == nil {
+		missed := f
+		missed.After = lastID
+		for _, rec := range h.buf.Records(missed) {
+			if err := es.Send(toEvent(rec)); err != nil {
+				return
+			}
+			f.After = rec.Seq
+		}
+	}
+
+	events := make(chan web.Event)
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case rec := <-sub:
+				if !f.Match(rec) {
+					continue
+				}
+				select {
+				case events <- toEvent(rec):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	es.Run(ctx, events, web.DefaultHeartbeat)
+}
+
+// toEvent converts a buffered record into a server-sent event identified by its sequence
+func toEvent(r logger.BufferedRecord) web.Event {
+	return web.Event{
+		ID:   strconv.FormatUint(r.Seq, 10),
+		Name: "log",
+		Data: toLogRecord(r),
+	}
+}
+
+// parseFilter reads the level, trace_id and since query parameters
+// Since accepts an RFC 3339 time or a duration before now such as 5m
+func parseFilter(q url.Values) (logger.Filter, error) {
+	f := logger.Filter{
+		Level:   logger.LevelDebug,
+		TraceID: q.Get("trace_id"),
+	}
+
+	if s := q.Get("level"); s != "" {
+		level, err := logger.ParseLevel(s)
+		if err != nil {
+			return logger.Filter{}, err
+		}
+		f.Level = level
+	}
+
+	if s := q.Get("since"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			f.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+			f.Since = t
+		} else {
+			return logger.Filter{}, fmt.Errorf("invalid since %q", s)
+		}
+	}
+
+	return f, nil
+}
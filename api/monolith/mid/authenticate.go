@@ -24,3 +24,21 @@ func Authenticate(auth *auth.Auth) web.Middleware {
 
 	return m
 }
+
+// AuthenticateWebSocket is HTTP middleware that authenticates websocket handshakes using the token
+// offered as a subprotocol or query parameter since browsers cannot set the authorization header
+func AuthenticateWebSocket(auth *auth.Auth) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			hdl := func(ctx context.Context) error {
+				return handler(ctx, w, r)
+			}
+
+			return mid.Authenticate(ctx, auth, "Bearer "+web.WebSocketToken(r), hdl)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,62 @@
+package mid_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	appMid "github.com/andrew-hayworth22/critiquefy-service/app/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_Authenticate(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", web.GetTraceID)
+	a := newAuth(t, log)
+
+	const subject = "c11eabcc-8492-4dfa-a586-97d9f1694a8a"
+	token := newToken(t, a, subject)
+
+	app := web.NewApp(nil, mid.Errors(log))
+	app.Handle("GET /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		userID, err := appMid.GetUserId(ctx)
+		if err != nil {
+			return err
+		}
+		return web.Respond(ctx, w, userID.String(), http.StatusOK)
+	}, mid.Authenticate(a))
+
+	cases := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{name: "Success_Bearer", authorization: "Bearer " + token, expected: http.StatusOK},
+		{name: "Fail_Missing", expected: http.StatusUnauthorized},
+		{name: "Fail_NoScheme", authorization: token, expected: http.StatusUnauthorized},
+		{name: "Fail_InvalidToken", authorization: "Bearer invalid", expected: http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/reviews", nil)
+			if c.authorization != "" {
+				r.Header.Set("Authorization", c.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expected {
+				t.Fatalf("Should respond %d, got %d: %s", c.expected, w.Code, w.Body.String())
+			}
+
+			if c.expected == http.StatusOK && w.Body.String() != `"`+subject+`"` {
+				t.Errorf("Should store the subject as the user ID, got %s", w.Body.String())
+			}
+		})
+	}
+}
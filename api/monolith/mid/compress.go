@@ -0,0 +1,340 @@
+package mid
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content encodings in order of preference
+const (
+	encodingZstd = "zstd"
+	encodingGzip = "gzip"
+)
+
+// CompressConfig represents when and how hard responses are compressed
+// A level of zero uses the default level of each encoding
+type CompressConfig struct {
+	MinSize int
+	Level   int
+}
+
+// Compress is HTTP middleware that compresses responses with the best encoding the client accepts
+// Responses smaller than the minimum size are written uncompressed
+func Compress(cfg CompressConfig) web.Middleware {
+	pools := newEncoderPools(cfg.Level)
+
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			addVary(w.Header(), "Accept-Encoding")
+
+			// Entity tags of compressed responses carry the encoding so conditional requests are compared against the tag the handler computes
+			stripETagEncoding(r.Header, "If-None-Match")
+			stripETagEncoding(r.Header, "If-Match")
+
+			// Upgraded connections such as websockets need the original writer to hijack
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+				return handler(ctx, w, r)
+			}
+
+			cw := compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        cfg.MinSize,
+				pools:          pools,
+			}
+
+			if err := handler(ctx, &cw, r); err != nil {
+				cw.close()
+				return err
+			}
+
+			return cw.close()
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// negotiateEncoding picks the supported encoding with the highest quality in the Accept-Encoding header
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	qualities := map[string]float64{}
+	wildcard := -1.0
+
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		qualities[name] = q
+	}
+
+	best := ""
+	bestQ := 0.0
+
+	for _, encoding := range []string{encodingZstd, encodingGzip} {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = wildcard
+		}
+
+		if q > bestQ {
+			best = encoding
+			bestQ = q
+		}
+	}
+
+	return best
+}
+
+// encoderPools reuses compressors between requests since they are expensive to allocate
+type encoderPools struct {
+	gzip sync.Pool
+	zstd sync.Pool
+}
+
+// newEncoderPools constructs pools for each supported encoding
+func newEncoderPools(level int) *encoderPools {
+	gzipLevel := gzip.DefaultCompression
+	zstdLevel := zstd.SpeedDefault
+	if level > 0 {
+		gzipLevel = min(level, gzip.BestCompression)
+		zstdLevel = zstd.EncoderLevelFromZstd(level)
+	}
+
+	p := encoderPools{}
+
+	p.gzip.New = func() any {
+		gw, _ := gzip.NewWriterLevel(io.Discard, gzipLevel)
+		return gw
+	}
+
+	p.zstd.New = func() any {
+		zw, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstdLevel))
+		return zw
+	}
+
+	return &p
+}
+
+// get fetches an encoder writing to w
+func (p *encoderPools) get(encoding string, w io.Writer) io.WriteCloser {
+	switch encoding {
+	case encodingZstd:
+		zw := p.zstd.Get().(*zstd.Encoder)
+		zw.Reset(w)
+		return zw
+	default:
+		gw := p.gzip.Get().(*gzip.Writer)
+		gw.Reset(w)
+		return gw
+	}
+}
+
+// put returns an encoder to its pool
+func (p *encoderPools) put(enc io.WriteCloser) {
+	switch enc := enc.(type) {
+	case *zstd.Encoder:
+		p.zstd.Put(enc)
+	case *gzip.Writer:
+		p.gzip.Put(enc)
+	}
+}
+
+// compressWriter buffers the start of a response until it knows whether it is worth compressing
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	minSize     int
+	pools       *encoderPools
+	statusCode  int
+	buf         []byte
+	enc         io.WriteCloser
+	wroteHeader bool
+	passthrough bool
+}
+
+// WriteHeader defers the status code until the encoding is decided
+func (cw *compressWriter) WriteHeader(statusCode int) {
+	if cw.wroteHeader || cw.statusCode != 0 {
+		return
+	}
+	cw.statusCode = statusCode
+
+	if !compressible(statusCode, cw.Header()) {
+		cw.passthrough = true
+		cw.writeHeader()
+	}
+}
+
+// Write buffers data until the minimum size is reached and then compresses it
+func (cw *compressWriter) Write(data []byte) (int, error) {
+	if cw.statusCode == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	switch {
+	case cw.passthrough:
+		return cw.ResponseWriter.Write(data)
+	case cw.enc != nil:
+		return cw.enc.Write(data)
+	}
+
+	cw.buf = append(cw.buf, data...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Flush compresses any buffered data and sends it to the client
+// Flushing commits the response, so the encoding starts even when nothing has been written yet
+func (cw *compressWriter) Flush() {
+	if cw.statusCode == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.passthrough && cw.enc == nil {
+		if err := cw.startEncoding(); err != nil {
+			return
+		}
+	}
+
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying response writer
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// startEncoding sets the encoding headers and writes the buffered data through the encoder
+func (cw *compressWriter) startEncoding() error {
+	h := cw.Header()
+	h.Set("Content-Encoding", cw.encoding)
+	h.Del("Content-Length")
+
+	// The compressed bytes differ from the identity representation so they need their own entity tag
+	if etag := h.Get("ETag"); strings.HasSuffix(etag, `"`) {
+		h.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+cw.encoding+`"`)
+	}
+
+	cw.writeHeader()
+	cw.enc = cw.pools.get(cw.encoding, cw.ResponseWriter)
+
+	buf := cw.buf
+	cw.buf = nil
+
+	_, err := cw.enc.Write(buf)
+	return err
+}
+
+// writeHeader sends the deferred status code to the client
+func (cw *compressWriter) writeHeader() {
+	if cw.wroteHeader || cw.statusCode == 0 {
+		return
+	}
+	cw.wroteHeader = true
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+}
+
+// close finishes the compressed stream or writes a response that was too small to compress
+func (cw *compressWriter) close() error {
+	if cw.enc != nil {
+		err := cw.enc.Close()
+		cw.pools.put(cw.enc)
+		return err
+	}
+
+	cw.writeHeader()
+	if len(cw.buf) > 0 {
+		if _, err := cw.ResponseWriter.Write(cw.buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addVary adds a header name to the Vary header unless it is already listed
+func addVary(h http.Header, name string) {
+	for _, v := range h.Values("Vary") {
+		for _, field := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), name) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", name)
+}
+
+// stripETagEncoding removes the encoding suffix added to compressed entity tags from a conditional request header
+func stripETagEncoding(h http.Header, name string) {
+	v := h.Get(name)
+	if v == "" {
+		return
+	}
+
+	for _, encoding := range []string{encodingZstd, encodingGzip} {
+		v = strings.ReplaceAll(v, "-"+encoding+`"`, `"`)
+	}
+	h.Set(name, v)
+}
+
+// compressible checks if a response can be compressed based on its status and headers
+func compressible(statusCode int, h http.Header) bool {
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified || statusCode < http.StatusOK {
+		return false
+	}
+
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	switch {
+	case contentType == "":
+		return true
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		return false
+	case strings.HasPrefix(contentType, "text/"):
+		return true
+	case strings.Contains(contentType, "json"), strings.Contains(contentType, "xml"), strings.Contains(contentType, "javascript"):
+		return true
+	}
+
+	return false
+}
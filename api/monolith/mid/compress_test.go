@@ -0,0 +1,157 @@
+package mid_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+	"github.com/klauspost/compress/zstd"
+)
+
+func Test_Compress(t *testing.T) {
+	app := web.NewApp(nil, mid.Compress(mid.CompressConfig{MinSize: 256}))
+	app.Handle("GET /small", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, []string{"small"}, http.StatusOK)
+	})
+	app.Handle("GET /export", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		aw, err := web.RespondArray(ctx, w, http.StatusOK)
+		if err != nil {
+			return err
+		}
+		for range 100 {
+			if err := aw.Write(strings.Repeat("review", 10)); err != nil {
+				return err
+			}
+		}
+		return aw.Close()
+	})
+
+	cases := []struct {
+		name             string
+		path             string
+		acceptEncoding   string
+		expectedEncoding string
+		expectedLen      int
+	}{
+		{name: "Success_Gzip", path: "/export", acceptEncoding: "gzip", expectedEncoding: "gzip", expectedLen: 100},
+		{name: "Success_Zstd", path: "/export", acceptEncoding: "gzip;q=0.5, zstd", expectedEncoding: "zstd", expectedLen: 100},
+		{name: "Success_Wildcard", path: "/export", acceptEncoding: "*", expectedEncoding: "zstd", expectedLen: 100},
+		{name: "Success_Identity", path: "/export", acceptEncoding: "gzip;q=0, zstd;q=0", expectedLen: 100},
+		{name: "Success_BelowMinSize", path: "/small", acceptEncoding: "gzip", expectedLen: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+			r.Header.Set("Accept-Encoding", c.acceptEncoding)
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Should respond with %d, got %d", http.StatusOK, w.Code)
+			}
+
+			encoding := w.Header().Get("Content-Encoding")
+			if encoding != c.expectedEncoding {
+				t.Fatalf("Should encode with %q, got %q", c.expectedEncoding, encoding)
+			}
+
+			var body io.Reader = w.Body
+			switch encoding {
+			case "gzip":
+				gr, err := gzip.NewReader(body)
+				if err != nil {
+					t.Fatalf("Should be able to read gzip body: %s", err)
+				}
+				body = gr
+			case "zstd":
+				zr, err := zstd.NewReader(body)
+				if err != nil {
+					t.Fatalf("Should be able to read zstd body: %s", err)
+				}
+				defer zr.Close()
+				body = zr
+			}
+
+			var got []string
+			if err := json.NewDecoder(body).Decode(&got); err != nil {
+				t.Fatalf("Should be able to decode body: %s", err)
+			}
+
+			if len(got) != c.expectedLen {
+				t.Errorf("Should decode %d elements, got %d", c.expectedLen, len(got))
+			}
+		})
+	}
+}
+
+func Test_Compress_ETag(t *testing.T) {
+	app := web.NewApp(nil, mid.Compress(mid.CompressConfig{MinSize: 16}))
+	app.Handle("GET /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, []string{strings.Repeat("review", 10)}, http.StatusOK)
+	})
+
+	get := func(acceptEncoding string, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/reviews", nil)
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, r)
+		return w
+	}
+
+	identity := get("", "").Header().Get("ETag")
+	gzipped := get("gzip", "")
+
+	if got := gzipped.Header().Get("ETag"); got != strings.TrimSuffix(identity, `"`)+`-gzip"` {
+		t.Errorf("Should tag the gzip representation apart from %s, got %s", identity, got)
+	}
+	if got := gzipped.Header().Values("Vary"); len(got) != 1 || got[0] != "Accept-Encoding" {
+		t.Errorf("Should vary on Accept-Encoding once, got %v", got)
+	}
+
+	if w := get("gzip", gzipped.Header().Get("ETag")); w.Code != http.StatusNotModified {
+		t.Errorf("Should respond %d to the compressed entity tag, got %d", http.StatusNotModified, w.Code)
+	}
+}
+
+func Test_Compress_FlushFirst(t *testing.T) {
+	app := web.NewApp(nil, mid.Compress(mid.CompressConfig{MinSize: 1024}))
+	app.Handle("GET /stream", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Content-Type", "application/json")
+		http.NewResponseController(w).Flush()
+		_, err := w.Write([]byte(`["review"]`))
+		return err
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	app.ServeHTTP(w, r)
+
+	// The result holds the headers as they were when the response was committed
+	if got := w.Result().Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Should set the encoding before flushing, got %q", got)
+	}
+
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Should be able to read gzip body: %s", err)
+	}
+
+	body, err := io.ReadAll(gr)
+	if err != nil || string(body) != `["review"]` {
+		t.Errorf("Should decode the body, got %q: %v", body, err)
+	}
+}
@@ -0,0 +1,152 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// CORSConfig represents the cross-origin resource sharing policy of the API
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// Default CORS values used when the config leaves them empty
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	defaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type"}
+)
+
+// Validate checks that credentials are only allowed for an explicit list of origins
+// Echoing any origin with credentials would let every site make authenticated requests on behalf of a user
+func (cfg CORSConfig) Validate() error {
+	if !cfg.AllowCredentials {
+		return nil
+	}
+
+	if len(cfg.AllowedOrigins) == 0 || matchAny(cfg.AllowedOrigins) {
+		return errors.New("cors: credentials require an explicit list of allowed origins")
+	}
+
+	return nil
+}
+
+// CORS is HTTP middleware that sets the CORS headers for allowed origins and answers preflight requests
+func CORS(cfg CORSConfig) web.Middleware {
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	exposeHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+
+	// An invalid config that slipped past Validate never allows credentials
+	allowCredentials := cfg.Validate() == nil && cfg.AllowCredentials
+	wildcard := matchAny(cfg.AllowedOrigins)
+
+	var maxAge string
+	if cfg.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if origin == "" || !MatchOrigin(cfg.AllowedOrigins, origin) {
+				return handler(ctx, w, r)
+			}
+
+			allowOrigin := origin
+			if wildcard {
+				allowOrigin = "*"
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			if allowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+				if maxAge != "" {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+
+				return handler(ctx, w, r)
+			}
+
+			if exposeHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// MatchOrigin checks if an origin is allowed by any of the origin patterns
+// Patterns may be "*", an exact origin, or contain a wildcard subdomain such as "https://*.critiquefy.com"
+func MatchOrigin(patterns []string, origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+		switch {
+		case pattern == "*":
+			return true
+
+		case pattern == origin:
+			return true
+
+		case strings.Contains(pattern, "*."):
+			prefix, suffix, _ := strings.Cut(pattern, "*")
+			if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+				continue
+			}
+
+			// The wildcard must cover at least one subdomain label
+			if len(origin) > len(prefix)+len(suffix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchAny checks if the origin patterns allow every origin
+func matchAny(patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "*" {
+			return true
+		}
+	}
+	return false
+}
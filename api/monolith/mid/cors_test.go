@@ -0,0 +1,173 @@
+package mid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_MatchOrigin(t *testing.T) {
+	cases := []struct {
+		name     string
+		patterns []string
+		origin   string
+		expected bool
+	}{
+		{name: "Success_Wildcard", patterns: []string{"*"}, origin: "https://critiquefy.com", expected: true},
+		{name: "Success_Exact", patterns: []string{"https://critiquefy.com"}, origin: "https://critiquefy.com", expected: true},
+		{name: "Success_ExactCase", patterns: []string{"https://Critiquefy.com"}, origin: "https://critiquefy.com", expected: true},
+		{name: "Success_Subdomain", patterns: []string{"https://*.critiquefy.com"}, origin: "https://app.critiquefy.com", expected: true},
+		{name: "Success_NestedSubdomain", patterns: []string{"https://*.critiquefy.com"}, origin: "https://beta.app.critiquefy.com", expected: true},
+		{name: "Fail_SubdomainApex", patterns: []string{"https://*.critiquefy.com"}, origin: "https://critiquefy.com", expected: false},
+		{name: "Fail_SubdomainScheme", patterns: []string{"https://*.critiquefy.com"}, origin: "http://app.critiquefy.com", expected: false},
+		{name: "Fail_SubdomainSuffix", patterns: []string{"https://*.critiquefy.com"}, origin: "https://app.critiquefy.com.evil.io", expected: false},
+		{name: "Fail_NoPatterns", patterns: nil, origin: "https://critiquefy.com", expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := mid.MatchOrigin(c.patterns, c.origin); got != c.expected {
+				t.Errorf("Should match origin %q against %v as %t, got %t", c.origin, c.patterns, c.expected, got)
+			}
+		})
+	}
+}
+
+func Test_CORS(t *testing.T) {
+	app := web.NewApp(nil, mid.CORS(mid.CORSConfig{
+		AllowedOrigins:   []string{"https://*.critiquefy.com"},
+		ExposedHeaders:   []string{"Location"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}))
+	app.EnableCORS()
+
+	app.Handle("GET /reviews/{id}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusOK)
+	})
+
+	t.Run("Success_Preflight", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodOptions, "/reviews/123", nil)
+		r.Header.Set("Origin", "https://app.critiquefy.com")
+		r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := httptest.NewRecorder()
+
+		app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Should answer preflight with %d, got %d", http.StatusNoContent, w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.critiquefy.com" {
+			t.Errorf("Should echo the origin, got %q", got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+			t.Errorf("Should allow credentials, got %q", got)
+		}
+		if got := w.Header().Get("Access-Control-Max-Age"); got != "3600" {
+			t.Errorf("Should set max age, got %q", got)
+		}
+	})
+
+	t.Run("Success_Request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/reviews/123", nil)
+		r.Header.Set("Origin", "https://app.critiquefy.com")
+		w := httptest.NewRecorder()
+
+		app.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Access-Control-Expose-Headers"); got != "Location" {
+			t.Errorf("Should expose headers, got %q", got)
+		}
+	})
+
+	t.Run("Fail_Origin", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodOptions, "/reviews/123", nil)
+		r.Header.Set("Origin", "https://evil.io")
+		r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := httptest.NewRecorder()
+
+		app.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Should not allow origin, got %q", got)
+		}
+	})
+}
+
+func Test_CORSConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     mid.CORSConfig
+		invalid bool
+	}{
+		{name: "Success_Wildcard", cfg: mid.CORSConfig{AllowedOrigins: []string{"*"}}},
+		{name: "Success_CredentialsExplicit", cfg: mid.CORSConfig{AllowedOrigins: []string{"https://*.critiquefy.com"}, AllowCredentials: true}},
+		{name: "Fail_CredentialsWildcard", cfg: mid.CORSConfig{AllowedOrigins: []string{"https://critiquefy.com", "*"}, AllowCredentials: true}, invalid: true},
+		{name: "Fail_CredentialsNoOrigins", cfg: mid.CORSConfig{AllowCredentials: true}, invalid: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.cfg.Validate(); (err != nil) != c.invalid {
+				t.Errorf("Should report the config as invalid %t, got %v", c.invalid, err)
+			}
+		})
+	}
+}
+
+func Test_CORS_CredentialsWildcard(t *testing.T) {
+	app := web.NewApp(nil, mid.CORS(mid.CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	}))
+
+	app.Handle("GET /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/reviews", nil)
+	r.Header.Set("Origin", "https://evil.io")
+	w := httptest.NewRecorder()
+
+	app.ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Should not echo the origin, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Should not allow credentials, got %q", got)
+	}
+}
+
+func Test_CORS_WildcardNames(t *testing.T) {
+	app := web.NewApp(nil, mid.CORS(mid.CORSConfig{
+		AllowedOrigins: []string{"https://critiquefy.com"},
+	}))
+	app.EnableCORS()
+
+	h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusOK)
+	}
+
+	app.Handle("GET /reviews/{id}", h)
+	app.Handle("DELETE /reviews/{reviewID}", h)
+	app.Handle("GET /files/{path...}", h)
+
+	for _, path := range []string{"/reviews/123", "/files/a/b"} {
+		r := httptest.NewRequest(http.MethodOptions, path, nil)
+		r.Header.Set("Origin", "https://critiquefy.com")
+		r.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+		w := httptest.NewRecorder()
+
+		app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Should answer preflight for %s with %d, got %d", path, http.StatusNoContent, w.Code)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package mid
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
@@ -23,7 +24,7 @@ func init() {
 	codeStatus[errs.AlreadyExists.Value()] = http.StatusConflict
 	codeStatus[errs.PermissionDenied.Value()] = http.StatusForbidden
 	codeStatus[errs.ResourceExhausted.Value()] = http.StatusTooManyRequests
-	codeStatus[errs.FailedPrecondition.Value()] = http.StatusBadRequest
+	codeStatus[errs.FailedPrecondition.Value()] = http.StatusPreconditionFailed
 	codeStatus[errs.Aborted.Value()] = http.StatusConflict
 	codeStatus[errs.OutOfRange.Value()] = http.StatusBadRequest
 	codeStatus[errs.Unimplemented.Value()] = http.StatusNotImplemented
@@ -37,13 +38,15 @@ func init() {
 func Errors(log *logger.Logger) web.Middleware {
 	m := func(handler web.Handler) web.Handler {
 		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			var handlerErr error
 			hdl := func(ctx context.Context) error {
-				return handler(ctx, w, r)
+				handlerErr = handler(ctx, w, r)
+				return handlerErr
 			}
 
 			if err := mid.Errors(ctx, log, hdl); err != nil {
 				errs := err.(errs.Error)
-				if err := web.Respond(ctx, w, errs, codeStatus[errs.Code.Value()]); err != nil {
+				if err := web.Respond(ctx, w, errs, errorStatus(handlerErr, errs.Code)); err != nil {
 					return err
 				}
 
@@ -60,3 +63,28 @@ func Errors(log *logger.Logger) web.Middleware {
 
 	return m
 }
+
+// errorStatus maps an error to its HTTP status code
+// Request body errors share application codes with other errors but have their own status codes
+func errorStatus(err error, code errs.ErrCode) int {
+	switch {
+	case errors.Is(err, web.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, web.ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return codeStatus[code.Value()]
+}
+
+// ErrorDocs documents the HTTP responses of application error codes for route documentation
+func ErrorDocs(codes ...errs.ErrCode) []web.ErrorDoc {
+	docs := make([]web.ErrorDoc, len(codes))
+	for i, code := range codes {
+		docs[i] = web.ErrorDoc{
+			Status: codeStatus[code.Value()],
+			Code:   code.String(),
+		}
+	}
+	return docs
+}
@@ -0,0 +1,117 @@
+package mid_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_Errors_PreconditionFailed(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", web.GetTraceID)
+
+	app := web.NewApp(nil, mid.Compress(mid.CompressConfig{}), mid.Logger(log), mid.Metrics(), mid.Errors(log), mid.Panics())
+
+	current := web.VersionETag("2")
+	app.Handle("PUT /reviews/{id}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if err := web.IfMatch(r, current); err != nil {
+			return err
+		}
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	})
+
+	cases := []struct {
+		name     string
+		ifMatch  string
+		expected int
+	}{
+		{name: "Success_Current", ifMatch: current, expected: http.StatusNoContent},
+		{name: "Success_NoHeader", expected: http.StatusNoContent},
+		{name: "Fail_Stale", ifMatch: web.VersionETag("1"), expected: http.StatusPreconditionFailed},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/reviews/123", nil)
+			if c.ifMatch != "" {
+				r.Header.Set("If-Match", c.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expected {
+				t.Errorf("Should respond %d, got %d: %s", c.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func Test_Errors_RequestBody(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", web.GetTraceID)
+
+	app := web.NewApp(nil, mid.Errors(log), mid.Idempotency(log, newIdempotencyStore(), time.Hour))
+	app.SetBodyLimit(32)
+
+	app.Handle("POST /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var review struct {
+			Title string `json:"title"`
+		}
+		if err := web.Decode(r, &review); err != nil {
+			return err
+		}
+		return web.Respond(ctx, w, review, http.StatusCreated)
+	})
+
+	cases := []struct {
+		name           string
+		contentType    string
+		body           string
+		key            string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "Success", contentType: "application/json", body: `{"title":"Dune"}`, expectedStatus: http.StatusCreated},
+		{name: "Fail_ContentType", contentType: "text/plain", body: `{"title":"Dune"}`, expectedStatus: http.StatusUnsupportedMediaType, expectedCode: "invalid_argument"},
+		{name: "Fail_TooLarge", contentType: "application/json", body: `{"title":"` + strings.Repeat("a", 64) + `"}`, expectedStatus: http.StatusRequestEntityTooLarge, expectedCode: "resource_exhausted"},
+		{name: "Fail_IdempotentTooLarge", contentType: "application/json", body: `{"title":"` + strings.Repeat("a", 64) + `"}`, key: "abc", expectedStatus: http.StatusRequestEntityTooLarge, expectedCode: "resource_exhausted"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(c.body))
+			r.Header.Set("Content-Type", c.contentType)
+			if c.key != "" {
+				r.Header.Set("Idempotency-Key", c.key)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expectedStatus {
+				t.Fatalf("Should respond %d, got %d: %s", c.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if c.expectedCode == "" {
+				return
+			}
+
+			var appErr errs.Error
+			if err := json.NewDecoder(w.Body).Decode(&appErr); err != nil {
+				t.Fatalf("Should decode the error: %s", err)
+			}
+			if appErr.Code.String() != c.expectedCode {
+				t.Errorf("Should respond with code %q, got %q", c.expectedCode, appErr.Code.String())
+			}
+		})
+	}
+}
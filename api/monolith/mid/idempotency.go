@@ -0,0 +1,180 @@
+package mid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/app/idempotency"
+	"github.com/andrew-hayworth22/critiquefy-service/app/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// maxIdempotencyKeyLen defines the longest idempotency key a client may send
+const maxIdempotencyKeyLen = 255
+
+// Idempotency is HTTP middleware that replays the stored response when an unsafe request is retried with the same Idempotency-Key
+// It must be registered after Authenticate so keys are scoped to the user making the request
+func Idempotency(log *logger.Logger, store idempotency.Storer, ttl time.Duration) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" || !unsafeMethod(r.Method) {
+				return handler(ctx, w, r)
+			}
+
+			if len(key) > maxIdempotencyKeyLen {
+				return errs.Newf(errs.InvalidArgument, "idempotency key must be at most %d characters", maxIdempotencyKeyLen)
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var mbe *http.MaxBytesError
+				if errors.As(err, &mbe) {
+					return fmt.Errorf("%w: limit is %d bytes", web.ErrBodyTooLarge, mbe.Limit)
+				}
+				return errs.Newf(errs.InvalidArgument, "cannot read request payload: %s", err)
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			caller := "ip:" + remoteIP(r)
+			if userID, err := mid.GetUserId(ctx); err == nil {
+				caller = "user:" + userID.String()
+			}
+
+			now := web.GetTime(ctx)
+			rec := idempotency.Record{
+				Caller:      caller,
+				Key:         key,
+				Fingerprint: idempotency.Fingerprint(r.Method, r.URL.RequestURI(), body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+
+			existing, reserved, err := store.Reserve(ctx, rec)
+			if err != nil {
+				return errs.New(errs.Unavailable, fmt.Errorf("idempotency: %w", err))
+			}
+
+			if !reserved {
+				return replay(ctx, w, rec, existing)
+			}
+
+			// Outer middleware has already set its headers, so only the ones the handler sets are stored
+			cw := newCaptureWriter(w)
+
+			if err := handler(ctx, cw, r); err != nil || cw.statusCode >= http.StatusInternalServerError {
+				if err := store.Release(ctx, caller, key); err != nil {
+					log.Error(ctx, "idempotency", "status", "releasing key", "ERROR", err)
+				}
+				return err
+			}
+
+			if err := store.Complete(ctx, caller, key, cw.response()); err != nil {
+				log.Error(ctx, "idempotency", "status", "completing key", "ERROR", err)
+			}
+
+			return nil
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// replay writes the stored response of a previous request made with the same key
+func replay(ctx context.Context, w http.ResponseWriter, rec idempotency.Record, existing idempotency.Record) error {
+	if existing.Fingerprint != rec.Fingerprint {
+		return errs.Newf(errs.FailedPrecondition, "idempotency key %q was already used for a different request", rec.Key)
+	}
+
+	if !existing.Completed() {
+		return errs.Newf(errs.Aborted, "request with idempotency key %q is still being processed", rec.Key)
+	}
+
+	// Headers set by outer middleware for this request, such as the trace ID, are kept over stored ones
+	for k, v := range existing.Response.Header {
+		if _, exists := w.Header()[k]; !exists {
+			w.Header()[k] = v
+		}
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+
+	return web.RespondBytes(ctx, w, existing.Response.Body, existing.Response.StatusCode)
+}
+
+// unsafeMethod checks if an HTTP method may change server state
+func unsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// captureWriter records the response written by a handler while passing it through to the client
+type captureWriter struct {
+	http.ResponseWriter
+	statusCode int
+	before     http.Header
+	header     http.Header
+	body       bytes.Buffer
+}
+
+// newCaptureWriter constructs a new captureWriter, remembering the headers already set by outer middleware
+func newCaptureWriter(w http.ResponseWriter) *captureWriter {
+	return &captureWriter{
+		ResponseWriter: w,
+		before:         w.Header().Clone(),
+	}
+}
+
+// WriteHeader records the status code and the headers set or changed by the handler
+func (cw *captureWriter) WriteHeader(statusCode int) {
+	if cw.statusCode == 0 {
+		cw.statusCode = statusCode
+		cw.header = make(http.Header)
+		for k, v := range cw.Header() {
+			if !slices.Equal(cw.before[k], v) {
+				cw.header[k] = slices.Clone(v)
+			}
+		}
+	}
+	cw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the body
+func (cw *captureWriter) Write(data []byte) (int, error) {
+	if cw.statusCode == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	cw.body.Write(data)
+	return cw.ResponseWriter.Write(data)
+}
+
+// Unwrap returns the underlying response writer
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// response returns the captured response
+func (cw *captureWriter) response() idempotency.Response {
+	statusCode := cw.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	return idempotency.Response{
+		StatusCode: statusCode,
+		Header:     cw.header,
+		Body:       cw.body.Bytes(),
+	}
+}
@@ -0,0 +1,152 @@
+package mid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/idempotency"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_Idempotency(t *testing.T) {
+	log := logger.New(&strings.Builder{}, logger.LevelInfo, "TEST", web.GetTraceID)
+
+	var calls int
+	app := web.NewApp(nil, mid.Errors(log), mid.Idempotency(log, newIdempotencyStore(), time.Hour))
+	app.Handle("POST /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		calls++
+		return web.Respond(ctx, w, map[string]int{"id": calls}, http.StatusCreated)
+	})
+
+	cases := []struct {
+		name           string
+		key            string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedCalls  int
+	}{
+		{name: "Success_First", key: "abc", body: `{"score":5}`, expectedStatus: http.StatusCreated, expectedBody: `{"id":1}`, expectedCalls: 1},
+		{name: "Success_Replay", key: "abc", body: `{"score":5}`, expectedStatus: http.StatusCreated, expectedBody: `{"id":1}`, expectedCalls: 1},
+		{name: "Success_NewKey", key: "def", body: `{"score":5}`, expectedStatus: http.StatusCreated, expectedBody: `{"id":2}`, expectedCalls: 2},
+		{name: "Success_NoKey", body: `{"score":5}`, expectedStatus: http.StatusCreated, expectedBody: `{"id":3}`, expectedCalls: 3},
+		{name: "Fail_DifferentBody", key: "abc", body: `{"score":1}`, expectedStatus: http.StatusPreconditionFailed, expectedCalls: 3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(c.body))
+			if c.key != "" {
+				r.Header.Set("Idempotency-Key", c.key)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expectedStatus {
+				t.Fatalf("Should respond with %d, got %d: %s", c.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if c.expectedBody != "" && w.Body.String() != c.expectedBody {
+				t.Errorf("Should respond with %s, got %s", c.expectedBody, w.Body.String())
+			}
+
+			if calls != c.expectedCalls {
+				t.Errorf("Should have called the handler %d times, got %d", c.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func Test_Idempotency_Headers(t *testing.T) {
+	log := logger.New(&strings.Builder{}, logger.LevelInfo, "TEST", web.GetTraceID)
+
+	// counter stands in for outer middleware such as the rate limiter that sets headers on every request
+	var requests int
+	counter := func(handler web.Handler) web.Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			requests++
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(100-requests))
+			return handler(ctx, w, r)
+		}
+	}
+
+	app := web.NewApp(nil, mid.Errors(log), counter, mid.Idempotency(log, newIdempotencyStore(), time.Hour))
+	app.Handle("POST /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Location", "/reviews/1")
+		return web.Respond(ctx, w, map[string]int{"id": 1}, http.StatusCreated)
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(`{"score":5}`))
+		r.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, r)
+		return w
+	}
+
+	first := send()
+	replay := send()
+
+	if replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("Should replay the stored response")
+	}
+	if got := replay.Header().Get("Location"); got != "/reviews/1" {
+		t.Errorf("Should replay the headers set by the handler, got Location %q", got)
+	}
+	if got := replay.Header().Get("RateLimit-Remaining"); got != "98" {
+		t.Errorf("Should keep the headers of outer middleware for the retry, got RateLimit-Remaining %q", got)
+	}
+	if first.Header().Get("X-Trace-Id") == replay.Header().Get("X-Trace-Id") {
+		t.Errorf("Should keep the trace ID of the retry, got %q for both", replay.Header().Get("X-Trace-Id"))
+	}
+}
+
+type idempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+}
+
+func newIdempotencyStore() *idempotencyStore {
+	return &idempotencyStore{records: make(map[string]idempotency.Record)}
+}
+
+func (s *idempotencyStore) Reserve(ctx context.Context, rec idempotency.Record) (idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[rec.Caller+rec.Key]; ok {
+		return existing, false, nil
+	}
+	s.records[rec.Caller+rec.Key] = rec
+
+	return rec, true, nil
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, caller string, key string, resp idempotency.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.records[caller+key]
+	rec.Response = &resp
+	s.records[caller+key] = rec
+
+	return nil
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, caller string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, caller+key)
+
+	return nil
+}
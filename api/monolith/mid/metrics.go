@@ -3,12 +3,13 @@ package mid
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/andrew-hayworth22/critiquefy-service/app/mid"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
 )
 
-// Metrics is HTTP middleware that updates our metrics with error, request, and goroutine data
+// Metrics is HTTP middleware that updates our metrics with request counts and latency by route pattern
 func Metrics() web.Middleware {
 	m := func(handler web.Handler) web.Handler {
 		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -16,7 +17,7 @@ func Metrics() web.Middleware {
 				return handler(ctx, w, r)
 			}
 
-			return mid.Metrics(ctx, hdl)
+			return mid.Metrics(ctx, routeLabel(r), r.Method, hdl)
 		}
 
 		return h
@@ -24,3 +25,11 @@ func Metrics() web.Middleware {
 
 	return m
 }
+
+// routeLabel returns the path of the pattern that matched the request so labels stay bounded
+func routeLabel(r *http.Request) string {
+	if _, path, found := strings.Cut(r.Pattern, " "); found {
+		return strings.TrimSpace(path)
+	}
+	return r.Pattern
+}
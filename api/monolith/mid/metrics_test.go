@@ -0,0 +1,54 @@
+package mid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/app/metrics"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_Metrics(t *testing.T) {
+	log := logger.New(&strings.Builder{}, logger.LevelInfo, "TEST", web.GetTraceID)
+
+	app := web.NewApp(nil, mid.Metrics(), mid.Errors(log), mid.Panics())
+	app.Handle("GET /metrics-test/{id}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		switch web.Param(r, "id") {
+		case "missing":
+			return errs.Newf(errs.NotFound, "review not found")
+		case "panic":
+			panic("boom")
+		}
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	})
+
+	for _, id := range []string{"1", "2", "missing", "panic"} {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics-test/"+id, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	expected := []string{
+		`critiquefy_http_requests_total{method="GET",route="/metrics-test/{id}",status="204"} 2`,
+		`critiquefy_http_requests_total{method="GET",route="/metrics-test/{id}",status="404"} 1`,
+		`critiquefy_http_requests_total{method="GET",route="/metrics-test/{id}",status="500"} 1`,
+		`critiquefy_http_request_duration_seconds_count{method="GET",route="/metrics-test/{id}",status="204"} 2`,
+		`critiquefy_errors_total{code="not_found"} 1`,
+		`critiquefy_errors_total{code="unknown"} 1`,
+		`critiquefy_panics_total 1`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("Should expose %s", line)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mid
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/app/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// RateLimit is HTTP middleware that limits requests by the named policy and sets the RateLimit headers
+// Requests with a valid bearer token are limited per user even when the route authenticates after the limiter
+func RateLimit(log *logger.Logger, limiter *ratelimit.Limiter, auth *auth.Auth, policy string) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			hdl := func(ctx context.Context) error {
+				return handler(ctx, w, r)
+			}
+
+			report := func(res ratelimit.Result) {
+				setRateLimitHeaders(w, res)
+			}
+
+			return mid.RateLimit(ctx, log, limiter, auth, policy, r.Header.Get("authorization"), remoteIP(r), report, hdl)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// setRateLimitHeaders writes the RateLimit response headers for a result
+func setRateLimitHeaders(w http.ResponseWriter, res ratelimit.Result) {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", res.Policy.Limit, ceilSeconds(res.Policy.Period)))
+
+	if !res.Allowed {
+		h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+	}
+}
+
+// remoteIP extracts the IP address of the client from the request
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds formats a duration as a whole number of seconds, rounding up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,172 @@
+package mid_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func Test_RateLimit(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", web.GetTraceID)
+	a := newAuth(t, log)
+
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Store:    ratelimit.NewMemoryStore(),
+		Policies: []ratelimit.Policy{{Name: ratelimit.PolicyDefault, Limit: 1, Period: time.Hour}},
+	})
+	if err != nil {
+		t.Fatalf("Should construct the limiter: %s", err)
+	}
+
+	// The limiter runs as app middleware, before the route authenticates
+	app := web.NewApp(nil, mid.Errors(log), mid.RateLimit(log, limiter, a, ratelimit.PolicyDefault))
+	app.Handle("GET /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	}, mid.Authenticate(a))
+	app.Handle("GET /public", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	})
+
+	alice := "Bearer " + newToken(t, a, "c11eabcc-8492-4dfa-a586-97d9f1694a8a")
+	bob := "Bearer " + newToken(t, a, "5cf37266-3473-4006-984f-9325122678b7")
+
+	// Every request comes from the same address so only per-user buckets let them through
+	cases := []struct {
+		name          string
+		path          string
+		authorization string
+		expected      int
+	}{
+		{name: "Success_Alice", path: "/reviews", authorization: alice, expected: http.StatusNoContent},
+		{name: "Success_Bob", path: "/reviews", authorization: bob, expected: http.StatusNoContent},
+		{name: "Success_Anonymous", path: "/public", expected: http.StatusNoContent},
+		{name: "Fail_AliceExhausted", path: "/reviews", authorization: alice, expected: http.StatusTooManyRequests},
+		{name: "Fail_AnonymousExhausted", path: "/public", expected: http.StatusTooManyRequests},
+		{name: "Fail_InvalidTokenLimitedByIP", path: "/public", authorization: "Bearer invalid", expected: http.StatusTooManyRequests},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+			if c.authorization != "" {
+				r.Header.Set("Authorization", c.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expected {
+				t.Errorf("Should respond %d, got %d: %s", c.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func Test_RateLimit_VerifiesOnce(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", web.GetTraceID)
+
+	ks := newKeyStore(t)
+	a, err := auth.New(auth.Config{Log: log, KeyLookup: ks, Issuer: "critiquefy"})
+	if err != nil {
+		t.Fatalf("Should construct auth: %s", err)
+	}
+
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Store:    ratelimit.NewMemoryStore(),
+		Policies: []ratelimit.Policy{{Name: ratelimit.PolicyDefault, Limit: 10, Period: time.Hour}},
+	})
+	if err != nil {
+		t.Fatalf("Should construct the limiter: %s", err)
+	}
+
+	app := web.NewApp(nil, mid.Errors(log), mid.RateLimit(log, limiter, a, ratelimit.PolicyDefault))
+	app.Handle("GET /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	}, mid.Authenticate(a))
+
+	r := httptest.NewRequest(http.MethodGet, "/reviews", nil)
+	r.Header.Set("Authorization", "Bearer "+newToken(t, a, "c11eabcc-8492-4dfa-a586-97d9f1694a8a"))
+	w := httptest.NewRecorder()
+
+	app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Should respond %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if n := ks.lookups.Load(); n != 1 {
+		t.Errorf("Should verify the token once across the limiter and Authenticate, got %d verifications", n)
+	}
+}
+
+// keyStore serves a single RSA key pair generated for the test and counts the public key lookups
+type keyStore struct {
+	private string
+	public  string
+	lookups *atomic.Int64
+}
+
+func (ks keyStore) PrivateKey(kid string) (string, error) { return ks.private, nil }
+
+func (ks keyStore) PublicKey(kid string) (string, error) {
+	ks.lookups.Add(1)
+	return ks.public, nil
+}
+
+func newAuth(t *testing.T, log *logger.Logger) *auth.Auth {
+	a, err := auth.New(auth.Config{Log: log, KeyLookup: newKeyStore(t), Issuer: "critiquefy"})
+	if err != nil {
+		t.Fatalf("Should construct auth: %s", err)
+	}
+
+	return a
+}
+
+func newKeyStore(t *testing.T) keyStore {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Should generate a key: %s", err)
+	}
+
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	publicDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+
+	return keyStore{
+		private: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		public:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		lookups: new(atomic.Int64),
+	}
+}
+
+func newToken(t *testing.T, a *auth.Auth, subject string) string {
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "critiquefy",
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Roles: []string{"user"},
+	}
+
+	tkn, err := a.GenerateToken("kid", claims)
+	if err != nil {
+		t.Fatalf("Should generate a token: %s", err)
+	}
+
+	return tkn
+}
@@ -0,0 +1,50 @@
+package mid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// APIVersion represents a version of the public API and its retirement schedule
+type APIVersion struct {
+	Name        string
+	Deprecation time.Time
+	Sunset      time.Time
+	Successor   string
+}
+
+// Version is HTTP middleware that records the API version of the request and warns clients of old versions
+func Version(v APIVersion) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			hdl := func(ctx context.Context) error {
+				return handler(ctx, w, r)
+			}
+
+			w.Header().Set("API-Version", v.Name)
+
+			if !v.Deprecation.IsZero() {
+				w.Header().Set("Deprecation", fmt.Sprintf("@%d", v.Deprecation.Unix()))
+			}
+
+			if !v.Sunset.IsZero() {
+				w.Header().Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if v.Successor != "" {
+				w.Header().Add("Link", fmt.Sprintf(`</%s>; rel="successor-version"`, v.Successor))
+			}
+
+			return mid.Version(ctx, v.Name, hdl)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -2,22 +2,66 @@ package mux
 
 import (
 	"os"
+	"time"
 
 	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
-	authAPI "github.com/andrew-hayworth22/critiquefy-service/api/monolith/route/auth"
 	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/route/sys"
 	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/app/idempotency"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/buildinfo"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/health"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Config contains the dependencies needed to construct the web API
+type Config struct {
+	Info        buildinfo.Info
+	Log         *logger.Logger
+	DB          *pgxpool.Pool
+	Health      *health.Registry
+	Auth        *auth.Auth
+	Shutdown    chan os.Signal
+	BodyLimit   int64
+	Tracer      *tracer.Tracer
+	CORS        mid.CORSConfig
+	Compress    mid.CompressConfig
+	RateLimiter *ratelimit.Limiter
+	Idempotency IdempotencyConfig
+}
+
+// IdempotencyConfig contains the storage used to replay retried requests on routes that create resources
+type IdempotencyConfig struct {
+	Store idempotency.Storer
+	TTL   time.Duration
+}
+
+// idempotent constructs the idempotency middleware that authenticated route groups register after Authenticate
+// It is nil when no store is configured so the routes are registered without it
+// No route creates resources yet so nothing registers it until the first authenticated unsafe route is added
+func idempotent(cfg Config) web.Middleware {
+	if cfg.Idempotency.Store == nil {
+		return nil
+	}
+	return mid.Idempotency(cfg.Log, cfg.Idempotency.Store, cfg.Idempotency.TTL)
+}
+
 // WebAPI constructs a web app with all routes bound to it
-func WebAPI(build string, log *logger.Logger, db *pgxpool.Pool, a *auth.Auth, shutdown chan os.Signal) *web.App {
-	app := web.NewApp(shutdown, mid.Logger(log), mid.Errors(log), mid.Metrics(), mid.Panics())
+func WebAPI(cfg Config) *web.App {
+	app := web.NewApp(cfg.Shutdown, mid.Compress(cfg.Compress), mid.Logger(cfg.Log), mid.Metrics(), mid.Errors(cfg.Log), mid.Panics(), mid.CORS(cfg.CORS))
+	app.EnableCORS()
+
+	if cfg.BodyLimit != 0 {
+		app.SetBodyLimit(cfg.BodyLimit)
+	}
+	app.SetTracer(cfg.Tracer)
 
-	sys.Routes(app, build, log, db)
-	authAPI.Routes(app, a)
+	sys.Routes(app, cfg.Info, cfg.Log, cfg.Health)
+	bindVersions(app, cfg)
+	bindOpenAPI(app)
 
 	return app
 }
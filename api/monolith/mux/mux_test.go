@@ -0,0 +1,53 @@
+package mux_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mux"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_RateLimitLogin(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" })
+
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Store: ratelimit.NewMemoryStore(),
+		Policies: []ratelimit.Policy{
+			{Name: ratelimit.PolicyDefault, Limit: 1, Period: time.Hour},
+			{Name: ratelimit.PolicyLogin, Limit: 3, Period: time.Hour},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a limiter: %s", err)
+	}
+
+	app := mux.WebAPI(mux.Config{
+		Log:         log,
+		RateLimiter: limiter,
+	})
+
+	for i := range 4 {
+		r := httptest.NewRequest(http.MethodPost, "/v1/auth/login", nil)
+		w := httptest.NewRecorder()
+
+		app.ServeHTTP(w, r)
+
+		expected := http.StatusOK
+		if i == 3 {
+			expected = http.StatusTooManyRequests
+		}
+
+		if w.Code != expected {
+			t.Fatalf("Should respond %d to login %d, got %d", expected, i+1, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "3" {
+			t.Errorf("Should only apply the login policy, got RateLimit-Limit %q", got)
+		}
+	}
+}
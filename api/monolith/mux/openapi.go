@@ -0,0 +1,43 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/openapi"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// OpenAPI generates the OpenAPI document describing every route of the web API
+func OpenAPI(cfg Config) openapi.Document {
+	return openAPIDocument(WebAPI(cfg))
+}
+
+// bindOpenAPI serves the OpenAPI document of the app
+func bindOpenAPI(app *web.App) {
+	var doc openapi.Document
+
+	h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, doc, http.StatusOK)
+	}
+
+	app.Handle("GET /openapi.json", h).Describe(web.RouteDoc{
+		Summary: "OpenAPI document describing this API",
+		Tags:    []string{"system"},
+	})
+
+	doc = openAPIDocument(app)
+}
+
+// openAPIDocument generates the OpenAPI document from the routes registered on the app
+func openAPIDocument(app *web.App) openapi.Document {
+	cfg := openapi.Config{
+		Title:       "Critiquefy API",
+		Version:     versions[len(versions)-1].Name,
+		Description: "Versions are selected by path prefix or an application/vnd.critiquefy.<version>+json Accept header and default to the latest.",
+		ErrorType:   errs.Error{},
+	}
+
+	return openapi.Generate(cfg, app.Routes())
+}
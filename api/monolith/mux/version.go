@@ -0,0 +1,88 @@
+package mux
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	authAPI "github.com/andrew-hayworth22/critiquefy-service/api/monolith/route/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/route/discussion"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// version pairs a version of the API with the routes it serves
+type version struct {
+	mid.APIVersion
+	routes func(r web.Router, cfg Config, hub *web.Hub)
+}
+
+// versions lists every supported version of the API from oldest to newest
+// The newest version serves requests that do not ask for a version
+var versions = []version{
+	{
+		APIVersion: mid.APIVersion{Name: "v1"},
+		routes:     v1Routes,
+	},
+}
+
+// v1Routes binds the routes of version 1 of the API
+// Login is limited by its own policy so every other route is grouped under the default policy
+func v1Routes(r web.Router, cfg Config, hub *web.Hub) {
+	authAPI.Routes(r, cfg.Auth, cfg.Log, cfg.RateLimiter)
+
+	limited := r.Group("", mid.RateLimit(cfg.Log, cfg.RateLimiter, cfg.Auth, ratelimit.PolicyDefault))
+	discussion.Routes(limited, cfg.Log, cfg.Auth, hub, cfg.CORS.AllowedOrigins)
+}
+
+// mediaTypeVersion matches the version requested by a vendor media type such as application/vnd.critiquefy.v1+json
+var mediaTypeVersion = regexp.MustCompile(`^application/vnd\.critiquefy\.(v[0-9]+)(\+json)?$`)
+
+// bindVersions registers the routes of every version under its path prefix and routes
+// unprefixed requests to the version named in the Accept header
+func bindVersions(app *web.App, cfg Config) {
+	hub := web.NewHub()
+
+	for _, v := range versions {
+		g := app.Group("/"+v.Name, mid.Version(v.APIVersion))
+		v.routes(g, cfg, hub)
+	}
+
+	latest := versions[len(versions)-1].Name
+
+	rewrite := func(r *http.Request) *http.Request {
+		if _, pattern := app.Handler(r); pattern != "" {
+			return r
+		}
+
+		name := acceptVersion(r.Header.Get("Accept"))
+		if name == "" {
+			name = latest
+		}
+
+		u := *r.URL
+		u.Path = "/" + name + r.URL.Path
+		u.RawPath = ""
+
+		r2 := *r
+		r2.URL = &u
+
+		return &r2
+	}
+
+	app.Rewrite(rewrite)
+}
+
+// acceptVersion extracts the API version from a vendor media type in the Accept header
+func acceptVersion(accept string) string {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+
+		if m := mediaTypeVersion.FindStringSubmatch(strings.TrimSpace(mediaType)); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,60 @@
+package mux_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mux"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_Versions(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" })
+
+	limiter, err := ratelimit.New(ratelimit.Config{})
+	if err != nil {
+		t.Fatalf("Should be able to create a limiter: %s", err)
+	}
+
+	app := mux.WebAPI(mux.Config{
+		Log:         log,
+		RateLimiter: limiter,
+	})
+
+	cases := []struct {
+		name            string
+		method          string
+		path            string
+		accept          string
+		expectedStatus  int
+		expectedVersion string
+	}{
+		{name: "Success_PathVersion", method: http.MethodPost, path: "/v1/auth/login", expectedStatus: http.StatusOK, expectedVersion: "v1"},
+		{name: "Success_MediaTypeVersion", method: http.MethodPost, path: "/auth/login", accept: "application/vnd.critiquefy.v1+json", expectedStatus: http.StatusOK, expectedVersion: "v1"},
+		{name: "Success_LatestVersion", method: http.MethodPost, path: "/auth/login", accept: "application/json", expectedStatus: http.StatusOK, expectedVersion: "v1"},
+		{name: "Success_Unversioned", method: http.MethodGet, path: "/liveness", expectedStatus: http.StatusOK},
+		{name: "Fail_UnknownVersion", method: http.MethodPost, path: "/auth/login", accept: "application/vnd.critiquefy.v9+json", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(c.method, c.path, nil)
+			r.Header.Set("Accept", c.accept)
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expectedStatus {
+				t.Fatalf("Should respond with %d, got %d", c.expectedStatus, w.Code)
+			}
+
+			if got := w.Header().Get("API-Version"); got != c.expectedVersion {
+				t.Errorf("Should be served by version %q, got %q", c.expectedVersion, got)
+			}
+		})
+	}
+}
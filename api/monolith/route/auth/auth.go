@@ -1,10 +1,19 @@
 package auth
 
 import (
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
 	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
 )
 
-func Routes(app *web.App, a *auth.Auth) {
-	app.Handle("POST /auth/login", login)
+func Routes(app web.Router, a *auth.Auth, log *logger.Logger, limiter *ratelimit.Limiter) {
+	g := app.Group("/auth")
+	g.Handle("POST /login", login, mid.RateLimit(log, limiter, a, ratelimit.PolicyLogin)).Describe(web.RouteDoc{
+		Summary: "Log in and receive a token",
+		Tags:    []string{"auth"},
+		Errors:  mid.ErrorDocs(errs.InvalidArgument, errs.Unauthenticated, errs.ResourceExhausted),
+	})
 }
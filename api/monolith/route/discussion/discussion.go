@@ -0,0 +1,136 @@
+package discussion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	appMid "github.com/andrew-hayworth22/critiquefy-service/app/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// maxCommentLen defines the longest comment that can be posted to a discussion
+const maxCommentLen = 2000
+
+// Types of messages sent to discussion clients
+const (
+	messageComment = "comment"
+	messageJoined  = "joined"
+	messageLeft    = "left"
+	messageError   = "error"
+)
+
+// incoming represents a message sent by a discussion client
+type incoming struct {
+	Body string `json:"body"`
+}
+
+// outgoing represents a message broadcast to discussion clients
+type outgoing struct {
+	Type     string    `json:"type"`
+	ReviewID string    `json:"review_id"`
+	UserID   string    `json:"user_id,omitempty"`
+	Body     string    `json:"body,omitempty"`
+	Online   int       `json:"online,omitempty"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+type api struct {
+	log *logger.Logger
+	hub *web.Hub
+	cfg web.WebSocketConfig
+}
+
+func newAPI(log *logger.Logger, hub *web.Hub, allowedOrigins []string) *api {
+	return &api{
+		log: log,
+		hub: hub,
+		cfg: web.WebSocketConfig{
+			Subprotocols: []string{"critiquefy.v1"},
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || mid.MatchOrigin(allowedOrigins, origin)
+			},
+		},
+	}
+}
+
+func (api *api) connect(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	reviewID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errors.New("invalid review id"))
+	}
+
+	userID, err := appMid.GetUserId(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	ws, err := web.UpgradeWebSocket(ctx, w, r, api.cfg)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	room := "review:" + reviewID.String()
+
+	api.hub.Join(room, ws)
+	api.broadcast(ctx, room, outgoing{Type: messageJoined, ReviewID: reviewID.String(), UserID: userID.String(), Online: api.hub.Count(room)})
+
+	api.log.Info(ctx, "discussion", "status", "joined", "review_id", reviewID, "user_id", userID)
+
+	onMessage := func(msg []byte) {
+		var in incoming
+		if err := json.Unmarshal(msg, &in); err != nil {
+			api.reply(ctx, ws, reviewID, "message must be a JSON object with a body")
+			return
+		}
+
+		body := strings.TrimSpace(in.Body)
+		if body == "" || len(body) > maxCommentLen {
+			api.reply(ctx, ws, reviewID, "comment must be between 1 and 2000 characters")
+			return
+		}
+
+		api.broadcast(ctx, room, outgoing{Type: messageComment, ReviewID: reviewID.String(), UserID: userID.String(), Body: body})
+	}
+
+	ws.Run(ctx, onMessage)
+
+	api.hub.Leave(room, ws)
+	api.broadcast(ctx, room, outgoing{Type: messageLeft, ReviewID: reviewID.String(), UserID: userID.String(), Online: api.hub.Count(room)})
+
+	api.log.Info(ctx, "discussion", "status", "left", "review_id", reviewID, "user_id", userID)
+
+	return nil
+}
+
+// broadcast sends a message to every client in a room
+func (api *api) broadcast(ctx context.Context, room string, msg outgoing) {
+	msg.SentAt = time.Now().UTC()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		api.log.Error(ctx, "discussion", "status", "encoding message", "ERROR", err)
+		return
+	}
+
+	api.hub.Broadcast(room, data)
+}
+
+// reply sends an error message back to a single client
+func (api *api) reply(ctx context.Context, ws *web.WebSocket, reviewID uuid.UUID, message string) {
+	data, err := json.Marshal(outgoing{Type: messageError, ReviewID: reviewID.String(), Body: message, SentAt: time.Now().UTC()})
+	if err != nil {
+		api.log.Error(ctx, "discussion", "status", "encoding message", "ERROR", err)
+		return
+	}
+
+	ws.Send(data)
+}
@@ -0,0 +1,26 @@
+package discussion
+
+import (
+	"net/http"
+
+	"github.com/andrew-hayworth22/critiquefy-service/api/monolith/mid"
+	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Routes(app web.Router, log *logger.Logger, a *auth.Auth, hub *web.Hub, allowedOrigins []string) {
+	api := newAPI(log, hub, allowedOrigins)
+
+	g := app.Group("/reviews/{id}", mid.AuthenticateWebSocket(a))
+	g.Handle("GET /discussion", api.connect).Describe(web.RouteDoc{
+		Summary:     "Join the live discussion of a review",
+		Description: "Upgrades to a websocket. Authenticate with a bearer.<token> subprotocol alongside critiquefy.v1 or an access_token query parameter. Clients send {\"body\": \"...\"} and receive comment, joined, left and error messages.",
+		Tags:        []string{"discussion"},
+		Status:      http.StatusSwitchingProtocols,
+		Response:    outgoing{},
+		Auth:        true,
+		Errors:      mid.ErrorDocs(errs.InvalidArgument, errs.Unauthenticated),
+	})
+}
@@ -1,14 +1,27 @@
 package sys
 
 import (
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/buildinfo"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/health"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func Routes(app *web.App, build string, log *logger.Logger, db *pgxpool.Pool) {
-	api := newAPI(build, log, db)
+func Routes(app web.Router, info buildinfo.Info, log *logger.Logger, checks *health.Registry) {
+	api := newAPI(info, log, checks)
 
-	app.HandleNoAppMiddleware("GET /liveness", api.liveness)
-	app.HandleNoAppMiddleware("GET /readiness", api.readiness)
+	app.HandleNoAppMiddleware("GET /liveness", api.liveness).Describe(web.RouteDoc{
+		Summary: "Check that the service is running",
+		Tags:    []string{"system"},
+	})
+	app.HandleNoAppMiddleware("GET /readiness", api.readiness).Describe(web.RouteDoc{
+		Summary:  "Check that the service can handle traffic",
+		Tags:     []string{"system"},
+		Response: readiness{},
+	})
+	app.HandleNoAppMiddleware("GET /version", api.version).Describe(web.RouteDoc{
+		Summary:  "Report the build of the running service",
+		Tags:     []string{"system"},
+		Response: version{},
+	})
 }
@@ -3,25 +3,24 @@ package sys
 import (
 	"context"
 	"net/http"
-	"time"
 
-	"github.com/andrew-hayworth22/critiquefy-service/business/data/sqldb"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/buildinfo"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/health"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type api struct {
-	build string
-	log   *logger.Logger
-	db    *pgxpool.Pool
+	info   buildinfo.Info
+	log    *logger.Logger
+	checks *health.Registry
 }
 
-func newAPI(build string, log *logger.Logger, db *pgxpool.Pool) *api {
+func newAPI(info buildinfo.Info, log *logger.Logger, checks *health.Registry) *api {
 	return &api{
-		build: build,
-		db:    db,
-		log:   log,
+		info:   info,
+		log:    log,
+		checks: checks,
 	}
 }
 
@@ -36,22 +35,74 @@ func (api *api) liveness(ctx context.Context, w http.ResponseWriter, r *http.Req
 }
 
 func (api *api) readiness(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	ctx, cancel := context.WithTimeout(ctx, time.Second)
-	defer cancel()
+	report := api.checks.Run(ctx)
+
+	for _, c := range report.Components {
+		if c.Status != health.StatusOK {
+			api.log.Warn(ctx, "readiness failure", "component", c.Name, "critical", c.Critical, "reason", c.Error)
+		}
+	}
 
-	status := "ok"
 	statusCode := http.StatusOK
-	if err := sqldb.StatusCheck(ctx, api.db); err != nil {
-		status = "db not ready"
-		statusCode = http.StatusInternalServerError
-		api.log.Info(ctx, "readiness failure", "status", status)
+	if !report.Ready() {
+		statusCode = http.StatusServiceUnavailable
 	}
 
-	data := struct {
-		Status string `json:"status"`
-	}{
-		Status: status,
+	return web.Respond(ctx, w, toReadiness(report), statusCode)
+}
+
+func (api *api) version(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	data := version{
+		Build:        api.info.Build,
+		Version:      api.info.Version,
+		GoVersion:    api.info.GoVersion,
+		Revision:     api.info.Revision,
+		RevisionTime: api.info.RevisionTime,
+		Modified:     api.info.Modified,
+	}
+
+	return web.Respond(ctx, w, data, http.StatusOK)
+}
+
+// version represents the build of the running service
+type version struct {
+	Build        string `json:"build"`
+	Version      string `json:"version"`
+	GoVersion    string `json:"go_version"`
+	Revision     string `json:"revision,omitempty"`
+	RevisionTime string `json:"revision_time,omitempty"`
+	Modified     bool   `json:"modified"`
+}
+
+// readiness represents the health of the service reported to load balancers and orchestrators
+type readiness struct {
+	Status     string      `json:"status"`
+	Components []component `json:"components"`
+}
+
+// component represents the health of one dependency
+// Errors are left out since the endpoint is public, they are reported on the debug server
+type component struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// toReadiness converts a health report into the readiness response
+func toReadiness(report health.Report) readiness {
+	components := make([]component, len(report.Components))
+	for i, c := range report.Components {
+		components[i] = component{
+			Name:      c.Name,
+			Status:    c.Status,
+			Critical:  c.Critical,
+			LatencyMS: float64(c.Latency.Microseconds()) / 1000,
+		}
 	}
 
-	return web.Respond(ctx, w, data, statusCode)
+	return readiness{
+		Status:     report.Status,
+		Components: components,
+	}
 }
@@ -0,0 +1,54 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNotFound is returned when no record exists for an idempotency key
+var ErrNotFound = errors.New("idempotency record not found")
+
+// Response represents a captured HTTP response that can be replayed
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Record represents the stored state of a request made with an idempotency key
+type Record struct {
+	Caller      string
+	Key         string
+	Fingerprint string
+	Response    *Response
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the original request has finished and its response was captured
+func (r Record) Completed() bool {
+	return r.Response != nil
+}
+
+// Storer represents the behavior required to persist idempotency records
+type Storer interface {
+	Reserve(ctx context.Context, rec Record) (Record, bool, error)
+	Complete(ctx context.Context, caller string, key string, resp Response) error
+	Release(ctx context.Context, caller string, key string) error
+}
+
+// Fingerprint produces a hash identifying the method, target, and body of a request
+func Fingerprint(method string, target string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(target))
+	h.Write([]byte{0})
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,159 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// sweepInterval defines how often expired records are purged
+const sweepInterval = time.Minute
+
+// postgresSchema creates the table used to store idempotency records
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	caller       TEXT NOT NULL,
+	key          TEXT NOT NULL,
+	fingerprint  TEXT NOT NULL,
+	status_code  INTEGER,
+	header       JSONB,
+	body         BYTEA,
+	created_at   TIMESTAMPTZ NOT NULL,
+	expires_at   TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (caller, key)
+);
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);`
+
+// PostgresStore persists idempotency records in Postgres
+type PostgresStore struct {
+	db        *pgxpool.Pool
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresStore constructs a new Postgres store and ensures its table exists
+func NewPostgresStore(ctx context.Context, db *pgxpool.Pool) (*PostgresStore, error) {
+	if _, err := db.Exec(ctx, postgresSchema); err != nil {
+		return nil, fmt.Errorf("creating idempotency schema: %w", err)
+	}
+
+	return &PostgresStore{db: db}, nil
+}
+
+// Reserve stores a new record for the key unless an unexpired record already exists
+// The existing record is returned when the key has already been used
+func (s *PostgresStore) Reserve(ctx context.Context, rec Record) (Record, bool, error) {
+	s.sweep(ctx, rec.CreatedAt)
+
+	const qInsert = `
+	INSERT INTO idempotency_keys (caller, key, fingerprint, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (caller, key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint,
+		status_code = NULL,
+		header = NULL,
+		body = NULL,
+		created_at = EXCLUDED.created_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at < EXCLUDED.created_at`
+
+	tag, err := s.db.Exec(ctx, qInsert, rec.Caller, rec.Key, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("reserving key: %w", err)
+	}
+
+	if tag.RowsAffected() == 1 {
+		return rec, true, nil
+	}
+
+	existing, err := s.query(ctx, rec.Caller, rec.Key)
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	return existing, false, nil
+}
+
+// Complete stores the captured response for a reserved key
+func (s *PostgresStore) Complete(ctx context.Context, caller string, key string, resp Response) error {
+	header, err := json.Marshal(resp.Header)
+	if err != nil {
+		return fmt.Errorf("marshalling header: %w", err)
+	}
+
+	const q = `UPDATE idempotency_keys SET status_code = $3, header = $4, body = $5 WHERE caller = $1 AND key = $2`
+
+	if _, err := s.db.Exec(ctx, q, caller, key, resp.StatusCode, header, resp.Body); err != nil {
+		return fmt.Errorf("completing key: %w", err)
+	}
+
+	return nil
+}
+
+// Release deletes a reserved key so the request can be retried
+func (s *PostgresStore) Release(ctx context.Context, caller string, key string) error {
+	const q = `DELETE FROM idempotency_keys WHERE caller = $1 AND key = $2`
+
+	if _, err := s.db.Exec(ctx, q, caller, key); err != nil {
+		return fmt.Errorf("releasing key: %w", err)
+	}
+
+	return nil
+}
+
+// query fetches the record for a key
+func (s *PostgresStore) query(ctx context.Context, caller string, key string) (Record, error) {
+	const q = `
+	SELECT fingerprint, status_code, header, body, created_at, expires_at
+	FROM idempotency_keys WHERE caller = $1 AND key = $2`
+
+	rec := Record{
+		Caller: caller,
+		Key:    key,
+	}
+
+	var statusCode *int
+	var header []byte
+	var body []byte
+
+	err := s.db.QueryRow(ctx, q, caller, key).Scan(&rec.Fingerprint, &statusCode, &header, &body, &rec.CreatedAt, &rec.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Record{}, ErrNotFound
+		}
+		return Record{}, fmt.Errorf("querying key: %w", err)
+	}
+
+	if statusCode != nil {
+		resp := Response{
+			StatusCode: *statusCode,
+			Body:       body,
+		}
+		if err := json.Unmarshal(header, &resp.Header); err != nil {
+			return Record{}, fmt.Errorf("unmarshalling header: %w", err)
+		}
+		rec.Response = &resp
+	}
+
+	return rec, nil
+}
+
+// sweep deletes expired records
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	const q = `DELETE FROM idempotency_keys WHERE expires_at < $1`
+	s.db.Exec(ctx, q, now)
+}
@@ -2,16 +2,39 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"expvar"
+	"fmt"
+	"net/http"
 	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// namespace prefixes the name of every Prometheus metric
+const namespace = "critiquefy"
+
 // metrics defines all of the metrics we are tracking for debugging/profiling
 type metrics struct {
 	goroutines *expvar.Int
 	requests   *expvar.Int
 	errors     *expvar.Int
 	panics     *expvar.Int
+	versions   *expvar.Map
+
+	registry        *prometheus.Registry
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	errorsTotal     *prometheus.CounterVec
+	panicsTotal     prometheus.Counter
+	versionsTotal   *prometheus.CounterVec
+	goroutinesGauge prometheus.Gauge
 }
 
 // Initializes metrics singleton
@@ -19,13 +42,110 @@ var m metrics
 
 func init() {
 	m = metrics{
-		expvar.NewInt("goroutines"),
-		expvar.NewInt("requests"),
-		expvar.NewInt("errors"),
-		expvar.NewInt("panics"),
+		goroutines: expvar.NewInt("goroutines"),
+		requests:   expvar.NewInt("requests"),
+		errors:     expvar.NewInt("errors"),
+		panics:     expvar.NewInt("panics"),
+		versions:   expvar.NewMap("versions"),
+
+		registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests handled by route pattern, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by route pattern, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		errorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of requests that failed by application error code.",
+		}, []string{"code"}),
+		panicsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "panics_total",
+			Help:      "Number of panics recovered while handling requests.",
+		}),
+		versionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_version_requests_total",
+			Help:      "Number of requests handled by API version.",
+		}, []string{"version"}),
+		goroutinesGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "goroutines",
+			Help:      "Number of goroutines at the last update.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestsTotal,
+		m.requestDuration,
+		m.errorsTotal,
+		m.panicsTotal,
+		m.versionsTotal,
+		m.goroutinesGauge,
+	)
+}
+
+// Handler serves the Prometheus metrics in the text exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Register adds collectors, such as those of other subsystems, to the Prometheus metrics
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// goroutinesUpdatedAt holds when UpdateGoroutinesEvery last ran as Unix nanoseconds
+var goroutinesUpdatedAt atomic.Int64
+
+// UpdateGoroutinesEvery sets the goroutine value on an interval until the context is cancelled
+func UpdateGoroutinesEvery(ctx context.Context, interval time.Duration) {
+	updateGoroutines(&m)
+	goroutinesUpdatedAt.Store(time.Now().UnixNano())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			updateGoroutines(&m)
+			goroutinesUpdatedAt.Store(time.Now().UnixNano())
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
+// CheckGoroutinesUpdated reports an error when UpdateGoroutinesEvery has not run within the max age
+func CheckGoroutinesUpdated(maxAge time.Duration) error {
+	last := goroutinesUpdatedAt.Load()
+	if last == 0 {
+		return errors.New("goroutine metrics have never been updated")
+	}
+
+	if since := time.Since(time.Unix(0, last)); since > maxAge {
+		return fmt.Errorf("goroutine metrics last updated %s ago", since.Round(time.Second))
+	}
+
+	return nil
+}
+
 type ctxKey int
 
 const key ctxKey = 1
@@ -38,9 +158,7 @@ func Set(ctx context.Context) context.Context {
 // UpdateGoroutines sets the goroutine value in the metrics data
 func UpdateGoroutines(ctx context.Context) int64 {
 	if v, ok := ctx.Value(key).(*metrics); ok {
-		g := int64(runtime.NumGoroutine())
-		v.goroutines.Set(g)
-		return g
+		return updateGoroutines(v)
 	}
 
 	return 0
@@ -57,11 +175,21 @@ func AddRequest(ctx context.Context) int64 {
 	return 0
 }
 
+// ObserveRequest records the status and latency of a request to a route
+func ObserveRequest(ctx context.Context, route string, method string, status int, since time.Duration) {
+	if v, ok := ctx.Value(key).(*metrics); ok {
+		code := strconv.Itoa(status)
+		v.requestsTotal.WithLabelValues(route, method, code).Inc()
+		v.requestDuration.WithLabelValues(route, method, code).Observe(since.Seconds())
+	}
+}
+
 // AddError increments the errors value in the metrics data
-func AddError(ctx context.Context) int64 {
+func AddError(ctx context.Context, code errs.ErrCode) int64 {
 	v, ok := ctx.Value(key).(*metrics)
 	if ok {
 		v.errors.Add(1)
+		v.errorsTotal.WithLabelValues(code.String()).Inc()
 		return v.errors.Value()
 	}
 
@@ -73,8 +201,25 @@ func AddPanic(ctx context.Context) int64 {
 	v, ok := ctx.Value(key).(*metrics)
 	if ok {
 		v.panics.Add(1)
+		v.panicsTotal.Inc()
 		return v.panics.Value()
 	}
 
 	return 0
 }
+
+// AddVersion increments the request count of an API version in the metrics data
+func AddVersion(ctx context.Context, version string) {
+	if v, ok := ctx.Value(key).(*metrics); ok {
+		v.versions.Add(version, 1)
+		v.versionsTotal.WithLabelValues(version).Inc()
+	}
+}
+
+// updateGoroutines sets the goroutine value of the expvar and Prometheus metrics
+func updateGoroutines(v *metrics) int64 {
+	g := int64(runtime.NumGoroutine())
+	v.goroutines.Set(g)
+	v.goroutinesGauge.Set(float64(g))
+	return g
+}
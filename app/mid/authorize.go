@@ -5,14 +5,19 @@ import (
 
 	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
 	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
 )
 
 // Authorize is middleware that asserts that the user making the request has a role
 func Authorize(ctx context.Context, auth *auth.Auth, role string, handler Handler) error {
+	ctx, span := tracer.Start(ctx, "mid.authorize")
+	defer span.End()
+	span.SetAttr("auth.role", role)
+
 	claims := GetClaims(ctx)
 
 	if err := auth.Authorize(ctx, claims, role); err != nil {
-		return errs.Newf(errs.PermissionDenied, "unauthorized: claims [%v] role [%v]: %s", claims, role, err)
+		return errs.Newf(errs.PermissionDenied, "unauthorized: subject [%s] role [%s]: %s", claims.Subject, role, err)
 	}
 
 	return handler(ctx)
@@ -2,23 +2,51 @@ package mid
 
 import (
 	"context"
+	"errors"
 
 	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/app/metrics"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
 )
 
 // Errors is middleware that handles errors gracefully
 func Errors(ctx context.Context, log *logger.Logger, handler Handler) error {
+	ctx, span := tracer.Start(ctx, "mid.errors")
+	defer span.End()
+
 	err := handler(ctx)
 	if err == nil {
 		return nil
 	}
 
+	span.SetError(err)
+
 	log.Error(ctx, "message", "ERROR", err.Error())
 
+	appErr := toError(err)
+	metrics.AddError(ctx, appErr.Code)
+
+	return appErr
+}
+
+// toError converts an error into an application Error, classifying known foundation errors
+func toError(err error) errs.Error {
 	if errs.IsError(err) {
 		return errs.GetError(err)
 	}
 
+	switch {
+	case errors.Is(err, web.ErrUnsupportedMediaType):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, web.ErrBodyTooLarge):
+		return errs.New(errs.ResourceExhausted, err)
+	case errors.Is(err, web.ErrInvalidBody):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, web.ErrPreconditionFailed):
+		return errs.New(errs.FailedPrecondition, err)
+	}
+
 	return errs.New(errs.Unknown, err)
 }
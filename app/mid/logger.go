@@ -6,11 +6,15 @@ import (
 	"time"
 
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
 	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
 )
 
 // Logger is middleware that logs before and after the request is processed
 func Logger(ctx context.Context, log *logger.Logger, path string, rawQuery string, method string, remoteAddr string, handler Handler) error {
+	ctx, span := tracer.Start(ctx, "mid.logger")
+	defer span.End()
+
 	v := web.GetValues(ctx)
 
 	if rawQuery != "" {
@@ -2,26 +2,27 @@ package mid
 
 import (
 	"context"
+	"time"
 
 	"github.com/andrew-hayworth22/critiquefy-service/app/metrics"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
 )
 
-// Metrics is middleware that updates our metrics with error, request, and goroutine data
-func Metrics(ctx context.Context, handler Handler) error {
+// Metrics is middleware that updates our metrics with request counts and latency
+// It must run outside of the Errors middleware so the status of failed requests is known
+func Metrics(ctx context.Context, route string, method string, handler Handler) error {
+	ctx, span := tracer.Start(ctx, "mid.metrics")
+	defer span.End()
+
 	ctx = metrics.Set(ctx)
 
 	err := handler(ctx)
 
-	n := metrics.AddRequest(ctx)
-
-	// Update goroutine count every 1000 requests
-	if n%1000 == 0 {
-		metrics.UpdateGoroutines(ctx)
-	}
+	v := web.GetValues(ctx)
 
-	if err != nil {
-		metrics.AddError(ctx)
-	}
+	metrics.AddRequest(ctx)
+	metrics.ObserveRequest(ctx, route, method, v.StatusCode, time.Since(v.Now))
 
 	return err
 }
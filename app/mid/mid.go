@@ -16,6 +16,7 @@ type ctxKey int
 const (
 	claimKey ctxKey = iota + 1
 	userIDKey
+	authorizationKey
 )
 
 // setClaims sets the claims in the context for later use
@@ -45,3 +46,14 @@ func GetUserId(ctx context.Context) (uuid.UUID, error) {
 	}
 	return v, nil
 }
+
+// setAuthorization records the authorization value whose token set the user in the context
+func setAuthorization(ctx context.Context, authorization string) context.Context {
+	return context.WithValue(ctx, authorizationKey, authorization)
+}
+
+// authenticated checks if the token of the authorization value has already been verified for the context
+func authenticated(ctx context.Context, authorization string) bool {
+	v, ok := ctx.Value(authorizationKey).(string)
+	return ok && v == authorization
+}
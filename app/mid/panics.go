@@ -6,14 +6,19 @@ import (
 	"runtime/debug"
 
 	"github.com/andrew-hayworth22/critiquefy-service/app/metrics"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
 )
 
 // Panics is middleware that catches and handles panics
 func Panics(ctx context.Context, handler Handler) (err error) {
+	ctx, span := tracer.Start(ctx, "mid.panics")
+	defer span.End()
+
 	defer func() {
 		if rec := recover(); rec != nil {
 			trace := debug.Stack()
 			err = fmt.Errorf("PANIC [%v] TRACE[%s]", rec, string(trace))
+			span.SetError(fmt.Errorf("PANIC [%v]", rec))
 
 			metrics.AddPanic(ctx)
 		}
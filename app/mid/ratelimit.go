@@ -0,0 +1,61 @@
+package mid
+
+import (
+	"context"
+	"strings"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/auth"
+	"github.com/andrew-hayworth22/critiquefy-service/app/errs"
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+)
+
+// RateLimitFn represents a function that reports the rate limit state to the caller
+type RateLimitFn func(res ratelimit.Result)
+
+// RateLimit is middleware that rejects requests once the caller has exhausted the policy
+// Callers are identified by their user ID when authenticated and by their remote IP otherwise
+func RateLimit(ctx context.Context, log *logger.Logger, limiter *ratelimit.Limiter, auth *auth.Auth, policy string, authorization string, remoteIP string, report RateLimitFn, handler Handler) error {
+	ctx, span := tracer.Start(ctx, "mid.ratelimit")
+	defer span.End()
+	span.SetAttr("ratelimit.policy", policy)
+
+	ctx, caller := rateLimitCaller(ctx, auth, authorization, remoteIP)
+
+	res, err := limiter.Allow(ctx, policy, caller)
+	if err != nil {
+		// Fail open so an unavailable store does not take the API down with it
+		log.Error(ctx, "rate limit", "policy", policy, "ERROR", err)
+		return handler(ctx)
+	}
+
+	report(res)
+
+	span.SetAttr("ratelimit.allowed", res.Allowed)
+
+	if !res.Allowed {
+		return errs.Newf(errs.ResourceExhausted, "rate limit exceeded: retry in %s", res.RetryAfter)
+	}
+
+	return handler(ctx)
+}
+
+// rateLimitCaller identifies the caller by the user ID set by Authenticate
+// The limiter usually runs before the route authenticates, so a valid bearer token identifies the user instead
+// The verified claims are kept in the returned context so Authenticate does not verify the token again
+// Invalid tokens fall back to the remote IP and are rejected later by Authenticate
+func rateLimitCaller(ctx context.Context, auth *auth.Auth, authorization string, remoteIP string) (context.Context, string) {
+	if userID, err := GetUserId(ctx); err == nil {
+		return ctx, "user:" + userID.String()
+	}
+
+	if auth != nil && strings.HasPrefix(authorization, "Bearer ") {
+		if authCtx, err := processJWT(ctx, auth, authorization); err == nil {
+			userID, _ := GetUserId(authCtx)
+			return authCtx, "user:" + userID.String()
+		}
+	}
+
+	return ctx, "ip:" + remoteIP
+}
@@ -0,0 +1,19 @@
+package mid
+
+import (
+	"context"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/metrics"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+)
+
+// Version is middleware that records which version of the API handled the request
+func Version(ctx context.Context, version string, handler Handler) error {
+	ctx, span := tracer.Start(ctx, "mid.version")
+	defer span.End()
+	span.SetAttr("api.version", version)
+
+	metrics.AddVersion(ctx, version)
+
+	return handler(ctx)
+}
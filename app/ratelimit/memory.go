@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval defines how often full buckets are purged from memory
+const sweepInterval = time.Minute
+
+// memoryBucket pairs a bucket with the policy it was last taken against
+type memoryBucket struct {
+	bucket
+	policy Policy
+}
+
+// MemoryStore keeps token buckets in process memory
+// Limits are only enforced per instance of the service
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore constructs a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+// Take removes a token from the bucket identified by key
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		s.buckets[key] = b
+	}
+	b.policy = policy
+
+	return b.take(policy, now), nil
+}
+
+// sweep removes buckets that have refilled since they are identical to new buckets
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if b.full(b.policy, now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresSchema creates the table used to share buckets between instances
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+	key        TEXT PRIMARY KEY,
+	tokens     DOUBLE PRECISION NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_expires_at_idx ON rate_limit_buckets (expires_at);`
+
+// PostgresStore keeps token buckets in Postgres so limits are shared by every instance of the service
+type PostgresStore struct {
+	db        *pgxpool.Pool
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresStore constructs a new Postgres store and ensures its table exists
+func NewPostgresStore(ctx context.Context, db *pgxpool.Pool) (*PostgresStore, error) {
+	if _, err := db.Exec(ctx, postgresSchema); err != nil {
+		return nil, fmt.Errorf("creating rate limit schema: %w", err)
+	}
+
+	return &PostgresStore{db: db}, nil
+}
+
+// Take removes a token from the bucket identified by key
+// The bucket row is locked for the duration of the transaction so concurrent instances cannot overspend it
+// A missing bucket is inserted full first so concurrent first requests for a key wait on the same row
+func (s *PostgresStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (res Result, err error) {
+	s.sweep(ctx, now)
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return Result{}, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	const qInsert = `
+	INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+	VALUES ($1, $2, $3, $3)
+	ON CONFLICT (key) DO NOTHING`
+
+	if _, err = tx.Exec(ctx, qInsert, key, float64(policy.Limit), now); err != nil {
+		return Result{}, fmt.Errorf("inserting bucket: %w", err)
+	}
+
+	const qSelect = `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`
+
+	var b bucket
+	err = tx.QueryRow(ctx, qSelect, key).Scan(&b.tokens, &b.updated)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, fmt.Errorf("selecting bucket: %w", err)
+	}
+
+	res = b.take(policy, now)
+
+	const qUpsert = `
+	INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (key) DO UPDATE SET tokens = $2, updated_at = $3, expires_at = $4`
+
+	if _, err = tx.Exec(ctx, qUpsert, key, b.tokens, b.updated, now.Add(res.Reset)); err != nil {
+		return Result{}, fmt.Errorf("updating bucket: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Result{}, fmt.Errorf("committing bucket: %w", err)
+	}
+
+	return res, nil
+}
+
+// sweep deletes buckets that have refilled since they are identical to new buckets
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	const q = `DELETE FROM rate_limit_buckets WHERE expires_at < $1`
+	s.db.Exec(ctx, q, now)
+}
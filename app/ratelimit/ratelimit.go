@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Policy represents how many requests a caller may make within a period
+// Callers may burst up to Limit requests, after which tokens refill evenly across the period
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// rate returns the number of tokens refilled per second
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// Result represents the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+	Policy     Policy
+}
+
+// Store represents storage for token buckets
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+}
+
+// Config represents the configuration needed for rate limiting
+type Config struct {
+	Store    Store
+	Policies []Policy
+}
+
+// Limiter applies named policies to callers
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+// Names of the policies applied by the API
+const (
+	PolicyDefault = "default"
+	PolicyLogin   = "login"
+)
+
+// fallbackPolicy is the policy applied when neither the requested nor the default policy has been configured
+var fallbackPolicy = Policy{Name: PolicyDefault, Limit: 300, Period: time.Minute}
+
+// New constructs a new Limiter
+func New(cfg Config) (*Limiter, error) {
+	l := Limiter{
+		store:    cfg.Store,
+		policies: make(map[string]Policy, len(cfg.Policies)+1),
+	}
+
+	l.policies[PolicyDefault] = fallbackPolicy
+
+	for _, p := range cfg.Policies {
+		if p.Name == "" {
+			return nil, errors.New("policy name required")
+		}
+		if p.Limit <= 0 || p.Period <= 0 {
+			return nil, fmt.Errorf("policy %q: limit and period must be positive", p.Name)
+		}
+		l.policies[p.Name] = p
+	}
+
+	if l.store == nil {
+		l.store = NewMemoryStore()
+	}
+
+	return &l, nil
+}
+
+// Policy fetches a policy by name, falling back to the default policy
+func (l *Limiter) Policy(name string) Policy {
+	if p, ok := l.policies[name]; ok {
+		return p
+	}
+	return l.policies[PolicyDefault]
+}
+
+// Allow takes a token from the caller's bucket for the named policy
+func (l *Limiter) Allow(ctx context.Context, name string, caller string) (Result, error) {
+	policy := l.Policy(name)
+	key := policy.Name + ":" + caller
+
+	res, err := l.store.Take(ctx, key, policy, time.Now().UTC())
+	if err != nil {
+		return Result{}, fmt.Errorf("taking token: %w", err)
+	}
+
+	return res, nil
+}
+
+// bucket represents the state of a single caller's token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills the bucket for the time elapsed and attempts to remove a token
+func (b *bucket) take(policy Policy, now time.Time) Result {
+	rate := policy.rate()
+	limit := float64(policy.Limit)
+
+	if b.updated.IsZero() {
+		b.tokens = limit
+	} else if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(limit, b.tokens+elapsed*rate)
+	}
+	b.updated = now
+
+	res := Result{
+		Limit:  policy.Limit,
+		Policy: policy,
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = seconds((limit - b.tokens) / rate)
+
+	return res
+}
+
+// full reports whether the bucket would have refilled completely by now
+func (b *bucket) full(policy Policy, now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*policy.rate() >= float64(policy.Limit)
+}
+
+// seconds converts fractional seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,87 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/app/ratelimit"
+)
+
+func Test_MemoryStore(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	policy := ratelimit.Policy{Name: "test", Limit: 3, Period: 3 * time.Second}
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name              string
+		elapsed           time.Duration
+		expectedAllowed   bool
+		expectedRemaining int
+	}{
+		{name: "Success_FirstRequest", expectedAllowed: true, expectedRemaining: 2},
+		{name: "Success_SecondRequest", expectedAllowed: true, expectedRemaining: 1},
+		{name: "Success_Burst", expectedAllowed: true, expectedRemaining: 0},
+		{name: "Fail_Exhausted", expectedAllowed: false, expectedRemaining: 0},
+		{name: "Success_Refilled", elapsed: time.Second, expectedAllowed: true, expectedRemaining: 0},
+		{name: "Success_FullyRefilled", elapsed: time.Hour, expectedAllowed: true, expectedRemaining: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			now = now.Add(c.elapsed)
+
+			res, err := store.Take(context.Background(), "ip:127.0.0.1", policy, now)
+			if err != nil {
+				t.Fatalf("Should be able to take a token: %s", err)
+			}
+
+			if res.Allowed != c.expectedAllowed {
+				t.Errorf("Should have allowed %t, got %t", c.expectedAllowed, res.Allowed)
+			}
+
+			if res.Remaining != c.expectedRemaining {
+				t.Errorf("Should have %d remaining, got %d", c.expectedRemaining, res.Remaining)
+			}
+
+			if !res.Allowed && res.RetryAfter <= 0 {
+				t.Errorf("Should report when to retry, got %s", res.RetryAfter)
+			}
+		})
+	}
+}
+
+func Test_Limiter(t *testing.T) {
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Policies: []ratelimit.Policy{
+			{Name: ratelimit.PolicyLogin, Limit: 1, Period: time.Minute},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a limiter: %s", err)
+	}
+
+	if _, err := limiter.Allow(context.Background(), ratelimit.PolicyLogin, "ip:127.0.0.1"); err != nil {
+		t.Fatalf("Should be able to take a token: %s", err)
+	}
+
+	res, err := limiter.Allow(context.Background(), ratelimit.PolicyLogin, "ip:127.0.0.1")
+	if err != nil {
+		t.Fatalf("Should be able to take a token: %s", err)
+	}
+	if res.Allowed {
+		t.Errorf("Should reject the second login attempt")
+	}
+
+	res, err = limiter.Allow(context.Background(), ratelimit.PolicyLogin, "ip:10.0.0.1")
+	if err != nil {
+		t.Fatalf("Should be able to take a token: %s", err)
+	}
+	if !res.Allowed {
+		t.Errorf("Should keep callers in separate buckets")
+	}
+
+	if p := limiter.Policy("unknown"); p.Name != ratelimit.PolicyDefault {
+		t.Errorf("Should fall back to the default policy, got %q", p.Name)
+	}
+}
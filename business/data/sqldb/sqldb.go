@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,20 +24,48 @@ var (
 )
 
 // Config defines what is needed to connect to the database
+// Zero values keep the pgx defaults, and a zero SlowQuery threshold disables slow query logging
 type Config struct {
-	URL          string
-	MaxIdleConns int
-	MaxOpenConns int
+	URL               string
+	MinIdleConns      int
+	MaxOpenConns      int
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	SlowQuery         time.Duration
+	Log               *logger.Logger
 }
 
 // Open creates a connection pool to the database based on the configuration
+// MinIdleConns is the number of connections kept open while the pool is idle
 func Open(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
-	fmt.Println(cfg.URL)
 	poolCfg, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("parsing pool config: %w", err)
 	}
 
+	if cfg.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(cfg.MaxOpenConns)
+	}
+	if cfg.MinIdleConns > 0 {
+		poolCfg.MinIdleConns = int32(min(cfg.MinIdleConns, int(poolCfg.MaxConns)))
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	tracers := []pgx.QueryTracer{queryTracer{}}
+	if cfg.SlowQuery > 0 && cfg.Log != nil {
+		tracers = append(tracers, slowQueryTracer{log: cfg.Log, threshold: cfg.SlowQuery})
+	}
+	poolCfg.ConnConfig.Tracer = multitracer.New(tracers...)
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to DB: %w", err)
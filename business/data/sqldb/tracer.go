@@ -0,0 +1,90 @@
+package sqldb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+	"github.com/jackc/pgx/v5"
+)
+
+// queryTracer records a span for every query run within a traced request
+type queryTracer struct{}
+
+// TraceQueryStart starts a span for the query as a child of the active span
+func (queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, span := tracer.StartClient(ctx, queryName(data.SQL))
+	span.SetAttr("db.system", "postgresql")
+	span.SetAttr("db.statement", data.SQL)
+
+	return ctx
+}
+
+// TraceQueryEnd records the outcome of the query and ends its span
+func (queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := tracer.FromContext(ctx)
+
+	span.SetAttr("db.rows_affected", data.CommandTag.RowsAffected())
+	span.SetError(data.Err)
+	span.End()
+}
+
+// queryName names a query span after the operation of the statement, such as db.SELECT
+func queryName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "db.query"
+	}
+
+	return "db." + strings.ToUpper(strings.TrimSuffix(fields[0], ";"))
+}
+
+// =============================================================================
+
+type ctxKey int
+
+const queryStartKey ctxKey = 1
+
+// queryStart represents a query that is in flight
+type queryStart struct {
+	sql string
+	at  time.Time
+}
+
+// slowQueryTracer logs queries that take longer than the threshold
+type slowQueryTracer struct {
+	log       *logger.Logger
+	threshold time.Duration
+}
+
+// TraceQueryStart records the statement and when it started
+func (t slowQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey, queryStart{sql: data.SQL, at: time.Now()})
+}
+
+// TraceQueryEnd logs the query if it ran longer than the threshold
+func (t slowQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey).(queryStart)
+	if !ok {
+		return
+	}
+
+	since := time.Since(start.at)
+	if since < t.threshold {
+		return
+	}
+
+	args := []any{"sql", compactSQL(start.sql), "duration", since.String(), "rows", data.CommandTag.RowsAffected()}
+	if data.Err != nil {
+		args = append(args, "ERROR", data.Err)
+	}
+
+	t.log.Warn(ctx, "slow query", args...)
+}
+
+// compactSQL collapses the whitespace of a statement so it logs on one line
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
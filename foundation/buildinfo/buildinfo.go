@@ -0,0 +1,54 @@
+// Package buildinfo reports how the running binary was built and when it started
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Info represents the build of the running binary
+// Revision, RevisionTime and Modified come from the VCS stamp and are empty when the binary was built without one
+type Info struct {
+	Build        string
+	Version      string
+	GoVersion    string
+	Revision     string
+	RevisionTime string
+	Modified     bool
+	StartTime    time.Time
+}
+
+// New constructs the info of the running binary with the build and version it was configured with
+// It should be called at startup since the start time is recorded when it is called
+func New(build string, version string) Info {
+	info := Info{
+		Build:     build,
+		Version:   version,
+		GoVersion: runtime.Version(),
+		StartTime: time.Now().UTC(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.RevisionTime = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+
+	return info
+}
+
+// Uptime returns how long the binary has been running
+func (i Info) Uptime() time.Duration {
+	return time.Since(i.StartTime)
+}
@@ -0,0 +1,308 @@
+// Package errtrack groups logged errors and reports them to a Sentry compatible endpoint
+package errtrack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+// Defaults used when the configuration leaves a setting empty
+const (
+	defaultMaxGroups     = 1000
+	defaultFlushInterval = 10 * time.Second
+)
+
+// errorKeys are the attributes whose value describes the error of a record
+var errorKeys = []string{"ERROR", "error"}
+
+// Config represents the configuration of a reporter
+// DSN has the form https://<key>@<host>/<project> and MaxGroups bounds the number of distinct errors tracked
+type Config struct {
+	DSN           string
+	Environment   string
+	Release       string
+	ServerName    string
+	MaxGroups     int
+	FlushInterval time.Duration
+	Client        *http.Client
+	UserIDFn      func(ctx context.Context) string
+	ErrorFn       func(err error)
+}
+
+// Group represents every occurrence of one error
+type Group struct {
+	Fingerprint string
+	Message     string
+	File        string
+	Count       int64
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	TraceID     string
+	UserID      string
+	Attributes  map[string]any
+
+	pending int64
+}
+
+// Reporter groups captured errors and delivers the groups that occurred since the last flush in batches
+type Reporter struct {
+	dsn       dsn
+	cfg       Config
+	mu        sync.Mutex
+	groups    map[string]*Group
+	dropped   atomic.Int64
+	flushMu   sync.Mutex
+	flushErr  error
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// New constructs a reporter and starts delivering errors in the background
+func New(cfg Config) (*Reporter, error) {
+	d, err := parseDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxGroups <= 0 {
+		cfg.MaxGroups = defaultMaxGroups
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.FlushInterval}
+	}
+	if cfg.UserIDFn == nil {
+		cfg.UserIDFn = func(context.Context) string { return "" }
+	}
+	if cfg.ErrorFn == nil {
+		cfg.ErrorFn = func(error) {}
+	}
+
+	r := Reporter{
+		dsn:    d,
+		cfg:    cfg,
+		groups: make(map[string]*Group),
+		done:   make(chan struct{}),
+	}
+
+	r.wg.Add(1)
+	go r.run()
+
+	return &r, nil
+}
+
+// Capture records an occurrence of the error in the log record
+// It matches logger.EventFn so it can be assigned to Events.Error
+func (r *Reporter) Capture(ctx context.Context, rec logger.Record) {
+	if r == nil {
+		return
+	}
+
+	msg := message(rec)
+	fp := msg + "|" + rec.File
+
+	traceID, _ := rec.Attributes["trace_id"].(string)
+	userID := r.cfg.UserIDFn(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, exists := r.groups[fp]
+	if !exists {
+		if len(r.groups) >= r.cfg.MaxGroups {
+			r.dropped.Add(1)
+			return
+		}
+
+		g = &Group{
+			Fingerprint: fp,
+			Message:     msg,
+			File:        rec.File,
+			FirstSeen:   rec.Time,
+		}
+		r.groups[fp] = g
+	}
+
+	g.Count++
+	g.pending++
+	g.LastSeen = rec.Time
+	g.TraceID = traceID
+	g.UserID = userID
+	g.Attributes = rec.Attributes
+}
+
+// Groups returns the tracked errors ordered by when they were last seen, newest first
+func (r *Reporter) Groups() []Group {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]Group, 0, len(r.groups))
+	for _, g := range r.groups {
+		list = append(list, *g)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastSeen.After(list[j].LastSeen)
+	})
+
+	return list
+}
+
+// Dropped returns how many errors were not tracked because MaxGroups was reached
+func (r *Reporter) Dropped() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.dropped.Load()
+}
+
+// Flush delivers the groups that occurred since the last flush
+// Groups that fail to deliver are retried on the next flush
+func (r *Reporter) Flush(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	batch := r.take()
+
+	var errs []error
+	for _, b := range batch {
+		if err := r.send(ctx, b.group, b.count); err != nil {
+			r.restore(b)
+			errs = append(errs, fmt.Errorf("sending %q: %w", b.group.Message, err))
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	r.flushMu.Lock()
+	r.flushErr = err
+	r.flushMu.Unlock()
+
+	return err
+}
+
+// Check reports the error of the last flush so a health check can show errors are not being delivered
+// A nil reporter is healthy since error tracking is disabled
+func (r *Reporter) Check(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	select {
+	case <-r.done:
+		return errors.New("reporter is shut down")
+	default:
+	}
+
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	return r.flushErr
+}
+
+// Shutdown stops the background delivery and flushes the pending errors
+func (r *Reporter) Shutdown(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+
+	ch := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(ch)
+	}()
+
+	select {
+	case <-ch:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return r.Flush(ctx)
+}
+
+// pendingGroup represents a snapshot of a group and the occurrences to report for it
+type pendingGroup struct {
+	group Group
+	count int64
+}
+
+// take returns the groups with unreported occurrences and marks them as reported
+func (r *Reporter) take() []pendingGroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var batch []pendingGroup
+	for _, g := range r.groups {
+		if g.pending == 0 {
+			continue
+		}
+
+		batch = append(batch, pendingGroup{group: *g, count: g.pending})
+		g.pending = 0
+	}
+
+	return batch
+}
+
+// restore returns the occurrences of a group that failed to deliver so they are reported again
+func (r *Reporter) restore(b pendingGroup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if g, exists := r.groups[b.group.Fingerprint]; exists {
+		g.pending += b.count
+	}
+}
+
+// run flushes the pending errors on an interval until the reporter shuts down
+func (r *Reporter) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.cfg.FlushInterval)
+			if err := r.Flush(ctx); err != nil {
+				r.cfg.ErrorFn(err)
+			}
+			cancel()
+
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// message describes the error of a record using its message and error attribute
+func message(rec logger.Record) string {
+	for _, k := range errorKeys {
+		if v, exists := rec.Attributes[k]; exists {
+			return fmt.Sprintf("%s: %v", rec.Message, v)
+		}
+	}
+	return rec.Message
+}
@@ -0,0 +1,179 @@
+package errtrack_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/errtrack"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+type userKey struct{}
+
+// stub records the events posted to a Sentry compatible endpoint
+type stub struct {
+	mu     sync.Mutex
+	auth   string
+	path   string
+	events []map[string]any
+	status int
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+
+	s.auth = r.Header.Get("X-Sentry-Auth")
+	s.path = r.URL.Path
+
+	sc := bufio.NewScanner(r.Body)
+	sc.Buffer(nil, 1<<20)
+
+	var lines []string
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+
+	var ev map[string]any
+	json.Unmarshal([]byte(lines[2]), &ev)
+	s.events = append(s.events, ev)
+}
+
+func Test_Reporter(t *testing.T) {
+	s := stub{}
+	srv := httptest.NewServer(&s)
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "http://", "http://public@", 1) + "/42"
+
+	reporter, err := errtrack.New(errtrack.Config{
+		DSN:           dsn,
+		Environment:   "test",
+		FlushInterval: time.Hour,
+		UserIDFn: func(ctx context.Context) string {
+			id, _ := ctx.Value(userKey{}).(string)
+			return id
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should construct the reporter: %s", err)
+	}
+	defer reporter.Shutdown(context.Background())
+
+	traceIDFn := func(ctx context.Context) string { return "trace-1" }
+	log := logger.NewWithEvents(io.Discard, logger.LevelInfo, "TEST", traceIDFn, logger.Events{Error: reporter.Capture})
+
+	ctx := context.WithValue(context.Background(), userKey{}, "user-1")
+
+	for range 3 {
+		log.Error(ctx, "message", "ERROR", "db is down")
+	}
+	log.Error(ctx, "message", "ERROR", "db is on fire")
+
+	groups := reporter.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("Should group errors by message and file, got %d groups", len(groups))
+	}
+
+	for _, g := range groups {
+		if g.Message == "message: db is down" && g.Count != 3 {
+			t.Errorf("Should count every occurrence, got %d", g.Count)
+		}
+		if g.TraceID != "trace-1" || g.UserID != "user-1" || !strings.HasPrefix(g.File, "errtrack_test.go:") {
+			t.Errorf("Should attach the trace, user and file, got %q %q %q", g.TraceID, g.UserID, g.File)
+		}
+	}
+
+	t.Run("Fail_EndpointDown", func(t *testing.T) {
+		s.status = http.StatusServiceUnavailable
+		defer func() { s.status = 0 }()
+
+		if err := reporter.Flush(context.Background()); err == nil {
+			t.Errorf("Should report the failed delivery")
+		}
+		if err := reporter.Check(context.Background()); err == nil {
+			t.Errorf("Should fail the health check while deliveries fail")
+		}
+	})
+
+	t.Run("Success_Flush", func(t *testing.T) {
+		if err := reporter.Flush(context.Background()); err != nil {
+			t.Fatalf("Should deliver the batch: %s", err)
+		}
+		if err := reporter.Check(context.Background()); err != nil {
+			t.Errorf("Should pass the health check once deliveries succeed: %s", err)
+		}
+
+		if len(s.events) != 2 {
+			t.Fatalf("Should retry and deliver one event per group, got %d", len(s.events))
+		}
+		if s.path != "/api/42/envelope/" || !strings.Contains(s.auth, "sentry_key=public") {
+			t.Errorf("Should post to the envelope endpoint of the project, got %q %q", s.path, s.auth)
+		}
+
+		for _, ev := range s.events {
+			msg := ev["message"].(map[string]any)["formatted"]
+			extra := ev["extra"].(map[string]any)
+			if msg == "message: db is down" && extra["count"] != float64(3) {
+				t.Errorf("Should report the occurrences of the batch, got %v", extra["count"])
+			}
+			if ev["user"].(map[string]any)["id"] != "user-1" || ev["tags"].(map[string]any)["trace_id"] != "trace-1" || ev["environment"] != "test" {
+				t.Errorf("Should send the user, trace and environment, got %v", ev)
+			}
+		}
+	})
+
+	t.Run("Success_NothingPending", func(t *testing.T) {
+		reporter.Flush(context.Background())
+		if len(s.events) != 2 {
+			t.Errorf("Should only deliver groups that occurred since the last flush, got %d events", len(s.events))
+		}
+	})
+}
+
+func Test_Reporter_Sampling(t *testing.T) {
+	reporter, err := errtrack.New(errtrack.Config{
+		DSN:           "http://public@localhost/42",
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Should construct the reporter: %s", err)
+	}
+	defer reporter.Shutdown(context.Background())
+
+	log := logger.NewWithOptions(io.Discard, logger.LevelInfo, "TEST", nil, logger.Options{
+		Events:   logger.Events{Error: reporter.Capture},
+		Sampling: logger.Sampling{Window: time.Hour},
+	})
+
+	ctx := context.Background()
+
+	for range 101 {
+		log.Error(ctx, "message", "ERROR", "db is down")
+	}
+	log.Error(ctx, "message", "ERROR", "db is on fire")
+
+	groups := reporter.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("Should only group the errors that were logged, got %d groups", len(groups))
+	}
+
+	for _, g := range groups {
+		if g.Message == "message: db is down" && g.Count != 101 {
+			t.Errorf("Should count the repeats sampling drops from the output, got %d", g.Count)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package errtrack
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clientName identifies the reporter to the Sentry endpoint
+const clientName = "critiquefy-errtrack/1.0"
+
+// dsn represents the parts of a Sentry DSN needed to deliver events
+type dsn struct {
+	endpoint  string
+	publicKey string
+}
+
+// parseDSN converts a DSN of the form https://<key>@<host>/<project> into the envelope endpoint of the project
+func parseDSN(s string) (dsn, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return dsn{}, fmt.Errorf("parsing dsn: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return dsn{}, errors.New("dsn requires an http or https scheme")
+	}
+
+	if u.User == nil || u.User.Username() == "" {
+		return dsn{}, errors.New("dsn requires a public key")
+	}
+
+	path, project, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if project == "" {
+		path, project = "", path
+	}
+	if project == "" {
+		return dsn{}, errors.New("dsn requires a project id")
+	}
+
+	endpoint := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   "/" + strings.Trim(path+"/api/"+project+"/envelope/", "/") + "/",
+	}
+
+	d := dsn{
+		endpoint:  endpoint.String(),
+		publicKey: u.User.Username(),
+	}
+
+	return d, nil
+}
+
+// event represents an error in the Sentry event payload format
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Platform    string            `json:"platform"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Culprit     string            `json:"culprit,omitempty"`
+	Message     eventMessage      `json:"message"`
+	Fingerprint []string          `json:"fingerprint"`
+	User        *eventUser        `json:"user,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra"`
+}
+
+// eventMessage represents the message of an event
+type eventMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+// eventUser represents the user affected by an event
+type eventUser struct {
+	ID string `json:"id"`
+}
+
+// newEvent converts a group and the occurrences since the last flush into an event
+func (r *Reporter) newEvent(g Group, count int64) event {
+	extra := map[string]any{
+		"count":       count,
+		"total_count": g.Count,
+		"first_seen":  g.FirstSeen.UTC(),
+		"last_seen":   g.LastSeen.UTC(),
+		"file":        g.File,
+	}
+	for k, v := range g.Attributes {
+		if _, exists := extra[k]; !exists {
+			extra[k] = fmt.Sprint(v)
+		}
+	}
+
+	ev := event{
+		EventID:     newEventID(),
+		Timestamp:   g.LastSeen.UTC(),
+		Level:       "error",
+		Logger:      "critiquefy",
+		Platform:    "go",
+		Release:     r.cfg.Release,
+		Environment: r.cfg.Environment,
+		ServerName:  r.cfg.ServerName,
+		Culprit:     g.File,
+		Message:     eventMessage{Formatted: g.Message},
+		Fingerprint: []string{g.Fingerprint},
+		Extra:       extra,
+	}
+
+	if g.UserID != "" {
+		ev.User = &eventUser{ID: g.UserID}
+	}
+
+	if g.TraceID != "" {
+		ev.Tags = map[string]string{"trace_id": g.TraceID}
+	}
+
+	return ev
+}
+
+// send posts the group as an event envelope to the Sentry endpoint
+func (r *Reporter) send(ctx context.Context, g Group, count int64) error {
+	ev := r.newEvent(g, count)
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	header, _ := json.Marshal(map[string]any{
+		"event_id": ev.EventID,
+		"sent_at":  time.Now().UTC(),
+	})
+	item, _ := json.Marshal(map[string]any{
+		"type":   "event",
+		"length": len(payload),
+	})
+
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(item)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.dsn.endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", clientName, r.dsn.publicKey))
+
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+
+	return nil
+}
+
+// newEventID generates a random event id
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,226 @@
+// Package health runs the registered health checks of the subsystems a service depends on
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults used when a check or the registry leaves a setting empty
+const (
+	defaultTimeout  = time.Second
+	defaultCacheTTL = 2 * time.Second
+)
+
+// Set of statuses reported for a component and the service as a whole
+// A failing non-critical component degrades the service while a failing critical component takes it down
+// A draining service is shutting down and reports it cannot take traffic whatever its components report
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+	StatusDraining = "draining"
+)
+
+// ErrTimeout is reported when a check does not finish within its timeout
+var ErrTimeout = errors.New("health check timed out")
+
+// CheckFn represents a function that returns an error when a subsystem is unhealthy
+type CheckFn func(ctx context.Context) error
+
+// Check represents a named health check of a subsystem
+// A critical check failing means the service cannot handle traffic
+type Check struct {
+	Name     string
+	Fn       CheckFn
+	Timeout  time.Duration
+	Critical bool
+}
+
+// Component represents the latest result of a check
+type Component struct {
+	Name                string
+	Status              string
+	Critical            bool
+	Timeout             time.Duration
+	Latency             time.Duration
+	Error               string
+	CheckedAt           time.Time
+	LastSuccess         time.Time
+	ConsecutiveFailures int
+}
+
+// Report represents the health of the service and each of its components
+type Report struct {
+	Status     string
+	Components []Component
+}
+
+// check holds the cached result of a registered check
+type check struct {
+	Check
+
+	mu      sync.Mutex
+	result  Component
+	expires time.Time
+}
+
+// Registry runs the registered checks concurrently and caches their results
+type Registry struct {
+	ttl      time.Duration
+	mu       sync.RWMutex
+	checks   map[string]*check
+	draining atomic.Bool
+}
+
+// New constructs a registry whose results are reused for the ttl so frequent probes do not overload dependencies
+func New(ttl time.Duration) *Registry {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &Registry{
+		ttl:    ttl,
+		checks: make(map[string]*check),
+	}
+}
+
+// Register adds a check, failing if the name is already registered
+func (r *Registry) Register(c Check) error {
+	if c.Name == "" || c.Fn == nil {
+		return errors.New("health check requires a name and a function")
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.checks[c.Name]; exists {
+		return fmt.Errorf("health check %q already registered", c.Name)
+	}
+
+	r.checks[c.Name] = &check{Check: c}
+
+	return nil
+}
+
+// Drain marks the service as shutting down so every later report is draining
+func (r *Registry) Drain() {
+	if r == nil {
+		return
+	}
+	r.draining.Store(true)
+}
+
+// Ready checks if the service should receive traffic based on a report
+func (rep Report) Ready() bool {
+	return rep.Status == StatusOK || rep.Status == StatusDegraded
+}
+
+// Run runs every check concurrently, reusing results that are younger than the ttl, and reports them ordered by name
+// A nil registry reports the service as healthy
+func (r *Registry) Run(ctx context.Context) Report {
+	if r == nil {
+		return Report{Status: StatusOK, Components: []Component{}}
+	}
+
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		checks = append(checks, c)
+	}
+	r.mu.RUnlock()
+
+	components := make([]Component, len(checks))
+
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for i, c := range checks {
+		go func() {
+			defer wg.Done()
+			components[i] = c.run(ctx, r.ttl)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+
+	status := StatusOK
+	for _, c := range components {
+		switch {
+		case c.Status == StatusOK:
+		case c.Critical:
+			status = StatusDown
+		case status == StatusOK:
+			status = StatusDegraded
+		}
+	}
+
+	if r.draining.Load() {
+		status = StatusDraining
+	}
+
+	return Report{Status: status, Components: components}
+}
+
+// run returns the cached result or runs the check when it has expired
+// Concurrent callers wait for the running check and share its result
+func (c *check) run(ctx context.Context, ttl time.Duration) Component {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.expires) {
+		return c.result
+	}
+
+	// The result is shared with other callers so it must not fail because this caller went away
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.Timeout)
+	defer cancel()
+
+	// The check runs separately so one that ignores its context cannot block the report
+	ch := make(chan error, 1)
+	go func() {
+		ch <- c.Fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-ch:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	result := Component{
+		Name:        c.Name,
+		Status:      StatusOK,
+		Critical:    c.Critical,
+		Timeout:     c.Timeout,
+		Latency:     time.Since(now),
+		CheckedAt:   now.UTC(),
+		LastSuccess: c.result.LastSuccess,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+		result.ConsecutiveFailures = c.result.ConsecutiveFailures + 1
+	} else {
+		result.LastSuccess = result.CheckedAt
+	}
+
+	c.result = result
+	c.expires = now.Add(ttl)
+
+	return result
+}
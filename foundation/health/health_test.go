@@ -0,0 +1,103 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/health"
+)
+
+func Test_Registry(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error { time.Sleep(time.Second); return nil }
+
+	cases := []struct {
+		name     string
+		checks   []health.Check
+		expected string
+	}{
+		{name: "Success_AllHealthy", checks: []health.Check{{Name: "db", Fn: ok, Critical: true}, {Name: "mail", Fn: ok}}, expected: health.StatusOK},
+		{name: "Success_Degraded", checks: []health.Check{{Name: "db", Fn: ok, Critical: true}, {Name: "mail", Fn: fail}}, expected: health.StatusDegraded},
+		{name: "Fail_CriticalDown", checks: []health.Check{{Name: "db", Fn: fail, Critical: true}, {Name: "mail", Fn: ok}}, expected: health.StatusDown},
+		{name: "Fail_Timeout", checks: []health.Check{{Name: "db", Fn: hang, Timeout: 10 * time.Millisecond, Critical: true}}, expected: health.StatusDown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := health.New(time.Minute)
+			for _, check := range c.checks {
+				if err := r.Register(check); err != nil {
+					t.Fatalf("Should register the check: %s", err)
+				}
+			}
+
+			report := r.Run(context.Background())
+			if report.Status != c.expected {
+				t.Errorf("Should report %q, got %q", c.expected, report.Status)
+			}
+			if len(report.Components) != len(c.checks) {
+				t.Errorf("Should report every component, got %d", len(report.Components))
+			}
+		})
+	}
+
+	t.Run("Fail_Duplicate", func(t *testing.T) {
+		r := health.New(time.Minute)
+		r.Register(health.Check{Name: "db", Fn: ok})
+
+		if err := r.Register(health.Check{Name: "db", Fn: ok}); err == nil {
+			t.Errorf("Should reject a duplicate name")
+		}
+	})
+
+	t.Run("Success_Cached", func(t *testing.T) {
+		var calls atomic.Int64
+		counted := func(ctx context.Context) error {
+			calls.Add(1)
+			return errors.New("down")
+		}
+
+		r := health.New(time.Minute)
+		r.Register(health.Check{Name: "db", Fn: counted})
+
+		for range 5 {
+			r.Run(context.Background())
+		}
+
+		if calls.Load() != 1 {
+			t.Errorf("Should reuse the result within the ttl, got %d calls", calls.Load())
+		}
+
+		c := r.Run(context.Background()).Components[0]
+		if c.Error != "down" || c.ConsecutiveFailures != 1 || !c.LastSuccess.IsZero() {
+			t.Errorf("Should keep the error details, got %+v", c)
+		}
+	})
+
+	t.Run("Success_CancelledCaller", func(t *testing.T) {
+		r := health.New(time.Minute)
+		r.Register(health.Check{Name: "db", Fn: func(ctx context.Context) error { return ctx.Err() }})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if status := r.Run(ctx).Status; status != health.StatusOK {
+			t.Errorf("Should not fail the shared result because the caller went away, got %q", status)
+		}
+	})
+
+	t.Run("Fail_Draining", func(t *testing.T) {
+		r := health.New(time.Minute)
+		r.Register(health.Check{Name: "db", Fn: ok, Critical: true})
+		r.Drain()
+
+		report := r.Run(context.Background())
+		if report.Status != health.StatusDraining || report.Ready() {
+			t.Errorf("Should not be ready while draining, got %q", report.Status)
+		}
+	})
+}
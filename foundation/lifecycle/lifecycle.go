@@ -0,0 +1,121 @@
+// Package lifecycle coordinates the graceful shutdown of a service
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+// defaultTimeout bounds the phases when the configuration leaves the timeout empty
+const defaultTimeout = 20 * time.Second
+
+// Config represents the configuration of a coordinator
+// DrainPeriod is how long traffic keeps being served after the service reports it is not ready
+// Timeout bounds the phases that run once draining is over
+type Config struct {
+	Log         *logger.Logger
+	DrainPeriod time.Duration
+	Timeout     time.Duration
+}
+
+// phase represents one step of the shutdown
+type phase struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Coordinator drains traffic and then stops the subsystems of a service in the order they were added
+type Coordinator struct {
+	cfg    Config
+	mu     sync.Mutex
+	drains []func()
+	phases []phase
+	once   sync.Once
+	err    error
+}
+
+// New constructs a coordinator
+func New(cfg Config) *Coordinator {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &Coordinator{cfg: cfg}
+}
+
+// OnDrain adds a function called when draining starts, such as failing readiness so load balancers stop routing to the service
+func (c *Coordinator) OnDrain(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.drains = append(c.drains, fn)
+}
+
+// Add appends a named phase that stops a subsystem
+// Phases run in the order they were added and a failing phase does not stop the ones after it
+func (c *Coordinator) Add(name string, fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.phases = append(c.phases, phase{name: name, fn: fn})
+}
+
+// Shutdown starts draining, waits for the drain period and runs every phase
+// The drain period ends early when ctx is cancelled and later calls return the result of the first
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		c.err = c.shutdown(ctx)
+	})
+
+	return c.err
+}
+
+// shutdown runs the shutdown sequence once
+func (c *Coordinator) shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	drains := c.drains
+	phases := c.phases
+	c.mu.Unlock()
+
+	log := c.cfg.Log
+
+	log.Info(ctx, "shutdown", "status", "draining", "period", c.cfg.DrainPeriod.String())
+
+	for _, fn := range drains {
+		fn()
+	}
+
+	if c.cfg.DrainPeriod > 0 {
+		timer := time.NewTimer(c.cfg.DrainPeriod)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			log.Warn(ctx, "shutdown", "status", "draining cut short", "reason", ctx.Err().Error())
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cfg.Timeout)
+	defer cancel()
+
+	var errs []error
+	for _, p := range phases {
+		log.Info(ctx, "shutdown", "status", "stopping", "phase", p.name)
+
+		start := time.Now()
+		if err := p.fn(ctx); err != nil {
+			log.Error(ctx, "shutdown", "status", "stopping failed", "phase", p.name, "ERROR", err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			continue
+		}
+
+		log.Info(ctx, "shutdown", "status", "stopped", "phase", p.name, "since", time.Since(start).String())
+	}
+
+	return errors.Join(errs...)
+}
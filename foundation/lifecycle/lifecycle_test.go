@@ -0,0 +1,74 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/lifecycle"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_Coordinator(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", nil)
+
+	t.Run("Success_Order", func(t *testing.T) {
+		var steps []string
+		step := func(name string, err error) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				steps = append(steps, name)
+				return err
+			}
+		}
+
+		c := lifecycle.New(lifecycle.Config{Log: log, DrainPeriod: 20 * time.Millisecond, Timeout: time.Second})
+		c.OnDrain(func() { steps = append(steps, "drain") })
+		c.Add("api", step("api", nil))
+		c.Add("workers", step("workers", errors.New("stuck")))
+		c.Add("db", step("db", nil))
+
+		start := time.Now()
+		err := c.Shutdown(context.Background())
+
+		if time.Since(start) < 20*time.Millisecond {
+			t.Errorf("Should wait for the drain period before stopping")
+		}
+		if got := strings.Join(steps, ","); got != "drain,api,workers,db" {
+			t.Errorf("Should drain and then run every phase in order, got %s", got)
+		}
+		if err == nil || !strings.Contains(err.Error(), "workers: stuck") {
+			t.Errorf("Should report the failed phase, got %v", err)
+		}
+
+		c.Shutdown(context.Background())
+		if len(steps) != 4 {
+			t.Errorf("Should only shut down once, got %d steps", len(steps))
+		}
+	})
+
+	t.Run("Success_DrainCutShort", func(t *testing.T) {
+		c := lifecycle.New(lifecycle.Config{Log: log, DrainPeriod: time.Minute, Timeout: time.Second})
+
+		var phaseErr error
+		c.Add("api", func(ctx context.Context) error {
+			phaseErr = ctx.Err()
+			return nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		c.Shutdown(ctx)
+
+		if time.Since(start) > time.Second {
+			t.Errorf("Should stop draining when the context is cancelled")
+		}
+		if phaseErr != nil {
+			t.Errorf("Should run the phases with their own timeout, got %v", phaseErr)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// subscriberQueue is the number of records a slow subscriber may fall behind before records are dropped for it
+const subscriberQueue = 256
+
+// BufferedRecord represents a record kept in a Buffer with its position in the sequence of records
+type BufferedRecord struct {
+	Seq uint64
+	Record
+}
+
+// Filter represents the records to return from a Buffer
+// Level is the minimum level, so a filter including debug records sets it to LevelDebug
+// The other fields match every record when zero
+type Filter struct {
+	Level   Level
+	TraceID string
+	Since   time.Time
+	After   uint64
+}
+
+// Match checks if a record passes the filter
+func (f Filter) Match(r BufferedRecord) bool {
+	if r.Seq <= f.After || r.Level < f.Level {
+		return false
+	}
+
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+
+	if f.TraceID != "" {
+		if id, _ := r.Attributes["trace_id"].(string); id != f.TraceID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Buffer keeps the most recent records in memory and streams new records to subscribers
+type Buffer struct {
+	mu      sync.RWMutex
+	records []BufferedRecord
+	next    int
+	seq     uint64
+	subs    map[chan BufferedRecord]struct{}
+}
+
+// NewBuffer constructs a buffer holding up to size records
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &Buffer{
+		records: make([]BufferedRecord, 0, size),
+		subs:    make(map[chan BufferedRecord]struct{}),
+	}
+}
+
+// Events returns the events that feed every logged record into the buffer
+func (b *Buffer) Events() Events {
+	return Events{
+		Debug: b.Add,
+		Info:  b.Add,
+		Warn:  b.Add,
+		Error: b.Add,
+	}
+}
+
+// Add stores a record, replacing the oldest once the buffer is full, and sends it to subscribers
+// Subscribers that are behind miss the record rather than block logging
+func (b *Buffer) Add(ctx context.Context, r Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	br := BufferedRecord{Seq: b.seq, Record: r}
+
+	if len(b.records) < cap(b.records) {
+		b.records = append(b.records, br)
+	} else {
+		b.records[b.next] = br
+		b.next = (b.next + 1) % len(b.records)
+	}
+
+	for ch := range b.subs {
+		select {
+		case ch <- br:
+		default:
+		}
+	}
+}
+
+// Records returns the buffered records that pass the filter from oldest to newest
+func (b *Buffer) Records(f Filter) []BufferedRecord {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	list := make([]BufferedRecord, 0)
+	for i := range b.records {
+		r := b.records[(b.next+i)%len(b.records)]
+		if f.Match(r) {
+			list = append(list, r)
+		}
+	}
+
+	return list
+}
+
+// Subscribe returns a channel receiving every record added after the call
+// The returned function ends the subscription and must be called once the caller stops reading
+func (b *Buffer) Subscribe() (<-chan BufferedRecord, func()) {
+	ch := make(chan BufferedRecord, subscriberQueue)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+		})
+	}
+
+	return ch, cancel
+}
@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_Buffer(t *testing.T) {
+	buf := logger.NewBuffer(3)
+
+	traceIDFn := func(ctx context.Context) string {
+		id, _ := ctx.Value(traceKey{}).(string)
+		return id
+	}
+	log := logger.NewWithEvents(io.Discard, logger.LevelDebug, "TEST", traceIDFn, buf.Events())
+
+	traced := context.WithValue(context.Background(), traceKey{}, "trace-1")
+
+	log.Info(context.Background(), "record 1")
+	log.Debug(traced, "record 2")
+	log.Info(context.Background(), "record 3")
+	log.Error(traced, "record 4", "password", "hunter2")
+
+	cases := []struct {
+		name     string
+		filter   logger.Filter
+		expected []string
+	}{
+		{name: "Success_KeepsNewest", filter: logger.Filter{Level: logger.LevelDebug}, expected: []string{"record 2", "record 3", "record 4"}},
+		{name: "Success_Level", filter: logger.Filter{Level: logger.LevelInfo}, expected: []string{"record 3", "record 4"}},
+		{name: "Success_TraceID", filter: logger.Filter{Level: logger.LevelDebug, TraceID: "trace-1"}, expected: []string{"record 2", "record 4"}},
+		{name: "Success_Since", filter: logger.Filter{Level: logger.LevelDebug, Since: time.Now().Add(time.Minute)}, expected: []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			records := buf.Records(c.filter)
+
+			got := make([]string, len(records))
+			for i, r := range records {
+				got[i] = r.Message
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(c.expected) {
+				t.Errorf("Should return %v, got %v", c.expected, got)
+			}
+		})
+	}
+
+	t.Run("Success_Redacted", func(t *testing.T) {
+		records := buf.Records(logger.Filter{Level: logger.LevelError})
+		if v := records[0].Attributes["password"]; v != "[REDACTED]" {
+			t.Errorf("Should keep redacted values, got %v", v)
+		}
+	})
+
+	t.Run("Success_Subscribe", func(t *testing.T) {
+		sub, cancel := buf.Subscribe()
+		defer cancel()
+
+		log.Warn(traced, "record 5")
+
+		select {
+		case r := <-sub:
+			if r.Message != "record 5" || r.Seq != 5 {
+				t.Errorf("Should receive record 5 with sequence 5, got %q %d", r.Message, r.Seq)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Should receive new records")
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Set of formats a logger can write
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// messageWidth is the column the attributes of a console record start at
+const messageWidth = 40
+
+// ANSI escape codes used to colorize console records
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+)
+
+// consoleHandler writes records as aligned, optionally colorized lines for reading in a terminal
+type consoleHandler struct {
+	mu       *sync.Mutex
+	w        io.Writer
+	color    bool
+	redactor *redactor
+	attrs    []slog.Attr
+	groups   []string
+}
+
+// newConsoleHandler constructs a handler that writes human readable records to w
+func newConsoleHandler(w io.Writer, color bool, rd *redactor) *consoleHandler {
+	return &consoleHandler{
+		mu:       &sync.Mutex{},
+		w:        w,
+		color:    color,
+		redactor: rd,
+	}
+}
+
+// Enabled reports true since the level is decided by the handlers wrapping it
+func (h *consoleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// WithAttrs returns a consoleHandler that writes attrs with every record
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append(append([]slog.Attr(nil), h.attrs...), h.qualify(attrs)...)
+	return &h2
+}
+
+// WithGroup returns a consoleHandler that qualifies the keys of later attributes with the group
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.groups = append(append([]string(nil), h.groups...), name)
+	return &h2
+}
+
+// Handle writes the record as a single line followed by any multi-line values
+func (h *consoleHandler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+
+	h.paint(&buf, colorDim, r.Time.Format("15:04:05.000"))
+	buf.WriteByte(' ')
+	h.paint(&buf, levelColor(r.Level), fmt.Sprintf("%-5s", r.Level.String()))
+	buf.WriteByte(' ')
+	buf.WriteString(r.Message)
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs()+len(h.attrs))
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	attrs = append(h.qualify(attrs), h.attrs...)
+
+	if len(attrs) > 0 && len(r.Message) < messageWidth {
+		buf.WriteString(strings.Repeat(" ", messageWidth-len(r.Message)))
+	}
+
+	var multiline []slog.Attr
+	for _, a := range attrs {
+		v := a.Value.String()
+		if strings.Contains(v, "\n") {
+			multiline = append(multiline, a)
+			continue
+		}
+
+		buf.WriteByte(' ')
+		h.paint(&buf, colorCyan, a.Key+"=")
+		buf.WriteString(quote(v))
+	}
+
+	if r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+
+		buf.WriteByte(' ')
+		h.paint(&buf, colorDim, fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line))
+	}
+
+	buf.WriteByte('\n')
+
+	for _, a := range multiline {
+		buf.WriteString("    ")
+		h.paint(&buf, colorCyan, a.Key+":")
+		buf.WriteByte('\n')
+
+		for _, line := range strings.Split(strings.TrimRight(a.Value.String(), "\n"), "\n") {
+			buf.WriteString("        ")
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// qualify resolves and redacts attributes, flattening groups into dotted keys
+func (h *consoleHandler) qualify(attrs []slog.Attr) []slog.Attr {
+	prefix := ""
+	if len(h.groups) > 0 {
+		prefix = strings.Join(h.groups, ".") + "."
+	}
+
+	out := make([]slog.Attr, 0, len(attrs))
+
+	var flatten func(prefix string, attrs []slog.Attr)
+	flatten = func(prefix string, attrs []slog.Attr) {
+		for _, a := range attrs {
+			a.Value = a.Value.Resolve()
+
+			if a.Value.Kind() == slog.KindGroup {
+				p := prefix
+				if a.Key != "" {
+					p += a.Key + "."
+				}
+				flatten(p, a.Value.Group())
+				continue
+			}
+
+			if a.Equal(slog.Attr{}) {
+				continue
+			}
+
+			a = h.redactor.attr(a)
+			a.Key = prefix + a.Key
+			out = append(out, a)
+		}
+	}
+	flatten(prefix, attrs)
+
+	return out
+}
+
+// paint writes s wrapped in the color when colors are enabled
+func (h *consoleHandler) paint(buf *bytes.Buffer, color string, s string) {
+	if !h.color {
+		buf.WriteString(s)
+		return
+	}
+
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(colorReset)
+}
+
+// levelColor returns the color a level is printed in
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed
+	case level >= slog.LevelWarn:
+		return colorYellow
+	case level >= slog.LevelInfo:
+		return colorBlue
+	default:
+		return colorDim
+	}
+}
+
+// quote quotes values that would be ambiguous when printed bare
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
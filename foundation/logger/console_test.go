@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_Console(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	var buf bytes.Buffer
+	var events int
+
+	log := logger.NewWithOptions(&buf, logger.LevelInfo, "TEST", nil, logger.Options{
+		Format: logger.FormatConsole,
+		Events: logger.Events{Error: func(ctx context.Context, r logger.Record) { events++ }},
+	})
+
+	log.Error(context.Background(), "request failed", "status", 500, "path", "/reviews?page=2", "password", "hunter2", "ERROR", "PANIC [boom]\ngoroutine 1 [running]:\nmain.main()")
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Should print the record and the multi-line value on 5 lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	first := lines[0]
+
+	expected := []string{
+		"ERROR request failed" + strings.Repeat(" ", 40-len("request failed")) + " status=500",
+		`path="/reviews?page=2"`,
+		"password=[REDACTED]",
+		"service=TEST",
+		"console_test.go:",
+	}
+	for _, e := range expected {
+		if !strings.Contains(first, e) {
+			t.Errorf("Should print %q, got %q", e, first)
+		}
+	}
+
+	if lines[1] != "    ERROR:" || lines[2] != "        PANIC [boom]" {
+		t.Errorf("Should indent multi-line values, got %q %q", lines[1], lines[2])
+	}
+
+	if events != 1 {
+		t.Errorf("Should still call the error event, got %d calls", events)
+	}
+}
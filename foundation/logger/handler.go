@@ -7,15 +7,17 @@ import (
 
 // logHandler wraps slog handler to capture the log level for event handling
 type logHandler struct {
-	handler slog.Handler
-	events  Events
+	handler  slog.Handler
+	events   Events
+	redactor *redactor
 }
 
 // newLogHandler creates a new logHandler
-func newLogHandler(handler slog.Handler, events Events) *logHandler {
+func newLogHandler(handler slog.Handler, events Events, rd *redactor) *logHandler {
 	return &logHandler{
-		handler: handler,
-		events:  events,
+		handler:  handler,
+		events:   events,
+		redactor: rd,
 	}
 }
 
@@ -27,13 +29,13 @@ func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // WithAttrs returns JSONHandler with h's attrs followed by attrs
 func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &logHandler{handler: h.handler.WithAttrs(attrs), events: h.events}
+	return &logHandler{handler: h.handler.WithAttrs(attrs), events: h.events, redactor: h.redactor}
 }
 
 // WithGroup returns new handler with group appended to receiver's groups
 // Keys of subsequent attributes should be qualified by the sequence of group names
 func (h *logHandler) WithGroup(name string) slog.Handler {
-	return &logHandler{handler: h.handler.WithGroup(name), events: h.events}
+	return &logHandler{handler: h.handler.WithGroup(name), events: h.events, redactor: h.redactor}
 }
 
 // Handle calls respective handlers depending on Events config
@@ -41,19 +43,19 @@ func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
 	switch r.Level {
 	case slog.LevelDebug:
 		if h.events.Debug != nil {
-			h.events.Debug(ctx, toRecord(r))
+			h.events.Debug(ctx, toRecord(r, h.redactor))
 		}
 	case slog.LevelError:
 		if h.events.Error != nil {
-			h.events.Error(ctx, toRecord(r))
+			h.events.Error(ctx, toRecord(r, h.redactor))
 		}
 	case slog.LevelWarn:
 		if h.events.Warn != nil {
-			h.events.Warn(ctx, toRecord(r))
+			h.events.Warn(ctx, toRecord(r, h.redactor))
 		}
 	case slog.LevelInfo:
 		if h.events.Info != nil {
-			h.events.Info(ctx, toRecord(r))
+			h.events.Info(ctx, toRecord(r, h.redactor))
 		}
 	}
 
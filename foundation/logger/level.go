@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UserIDFn represents a function that returns the ID of the authenticated user from the context
+type UserIDFn func(ctx context.Context) string
+
+// Set of scopes a debug elevation can apply to
+const (
+	ScopeTrace = "trace"
+	ScopeUser  = "user"
+)
+
+// Elevation represents debug logging that is temporarily enabled for one trace or user
+type Elevation struct {
+	Scope   string    `json:"scope"`
+	ID      string    `json:"id"`
+	Expires time.Time `json:"expires"`
+}
+
+// levels decides which records are logged, allowing the minimum level and elevations to change at runtime
+type levels struct {
+	min       slog.LevelVar
+	traceIDFn TraceIDFn
+	userIDFn  atomic.Pointer[UserIDFn]
+
+	mu         sync.RWMutex
+	elevations map[string]Elevation
+	count      atomic.Int64
+}
+
+// newLevels constructs the level state with an initial minimum level
+func newLevels(minLevel Level, traceIDFn TraceIDFn) *levels {
+	l := levels{
+		traceIDFn:  traceIDFn,
+		elevations: make(map[string]Elevation),
+	}
+	l.min.Set(slog.Level(minLevel))
+
+	return &l
+}
+
+// enabled checks if a record at the level should be logged for the request in the context
+func (l *levels) enabled(ctx context.Context, level slog.Level) bool {
+	if level >= l.min.Level() {
+		return true
+	}
+
+	if level < slog.LevelDebug || l.count.Load() == 0 {
+		return false
+	}
+
+	return l.elevated(ctx)
+}
+
+// elevated checks if debug logging is enabled for the trace or user of the context
+func (l *levels) elevated(ctx context.Context) bool {
+	var keys []string
+	if l.traceIDFn != nil {
+		keys = append(keys, elevationKey(ScopeTrace, l.traceIDFn(ctx)))
+	}
+	if fn := l.userIDFn.Load(); fn != nil {
+		if userID := (*fn)(ctx); userID != "" {
+			keys = append(keys, elevationKey(ScopeUser, userID))
+		}
+	}
+
+	now := time.Now()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, key := range keys {
+		if e, ok := l.elevations[key]; ok && now.Before(e.Expires) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// elevate enables debug logging for a trace or user until the duration passes
+func (l *levels) elevate(scope string, id string, d time.Duration) Elevation {
+	e := Elevation{
+		Scope:   scope,
+		ID:      id,
+		Expires: time.Now().Add(d).UTC(),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	l.elevations[elevationKey(scope, id)] = e
+	l.count.Store(int64(len(l.elevations)))
+
+	return e
+}
+
+// revoke removes the elevation of a trace or user
+func (l *levels) revoke(scope string, id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := elevationKey(scope, id)
+	_, exists := l.elevations[key]
+	delete(l.elevations, key)
+
+	l.prune()
+	l.count.Store(int64(len(l.elevations)))
+
+	return exists
+}
+
+// list returns the elevations that have not expired ordered by expiry
+func (l *levels) list() []Elevation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	l.count.Store(int64(len(l.elevations)))
+
+	list := make([]Elevation, 0, len(l.elevations))
+	for _, e := range l.elevations {
+		list = append(list, e)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Expires.Before(list[j].Expires)
+	})
+
+	return list
+}
+
+// prune removes expired elevations, the caller must hold the lock
+func (l *levels) prune() {
+	now := time.Now()
+	for key, e := range l.elevations {
+		if !now.Before(e.Expires) {
+			delete(l.elevations, key)
+		}
+	}
+}
+
+// elevationKey identifies the elevation of a trace or user
+func elevationKey(scope string, id string) string {
+	return scope + ":" + id
+}
+
+// =============================================================================
+
+// levelHandler gates records by the runtime level state before they reach the wrapped handler
+type levelHandler struct {
+	handler slog.Handler
+	levels  *levels
+}
+
+// Enabled reports whether the record should be logged based on the level state
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.levels.enabled(ctx, level)
+}
+
+// WithAttrs returns a levelHandler whose wrapped handler has the attrs
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{handler: h.handler.WithAttrs(attrs), levels: h.levels}
+}
+
+// WithGroup returns a levelHandler whose wrapped handler has the group
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{handler: h.handler.WithGroup(name), levels: h.levels}
+}
+
+// Handle passes the record to the wrapped handler
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+// =============================================================================
+
+// String returns the name of the level
+func (l Level) String() string {
+	return slog.Level(l).String()
+}
+
+// ParseLevel converts a level name such as debug or WARN into a Level
+func ParseLevel(s string) (Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+
+	return Level(l), nil
+}
@@ -0,0 +1,78 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+type traceKey struct{}
+
+func Test_Levels(t *testing.T) {
+	var buf bytes.Buffer
+
+	traceIDFn := func(ctx context.Context) string {
+		id, _ := ctx.Value(traceKey{}).(string)
+		return id
+	}
+	log := logger.New(&buf, logger.LevelInfo, "TEST", traceIDFn)
+	log.SetUserIDFn(func(ctx context.Context) string { return "" })
+
+	traced := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	other := context.WithValue(context.Background(), traceKey{}, "trace-2")
+
+	if _, err := log.Elevate(logger.ScopeTrace, "trace-1", time.Minute); err != nil {
+		t.Fatalf("Should be able to elevate a trace: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		setup    func()
+		expected bool
+	}{
+		{name: "Success_Elevated", ctx: traced, expected: true},
+		{name: "Fail_NotElevated", ctx: other, expected: false},
+		{name: "Success_LevelChanged", ctx: other, setup: func() { log.SetLevel(logger.LevelDebug) }, expected: true},
+		{name: "Fail_Revoked", ctx: traced, setup: func() { log.SetLevel(logger.LevelInfo); log.Revoke(logger.ScopeTrace, "trace-1") }, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.setup != nil {
+				c.setup()
+			}
+			buf.Reset()
+
+			log.Debug(c.ctx, "debug record")
+
+			if got := strings.Contains(buf.String(), "debug record"); got != c.expected {
+				t.Errorf("Should log the debug record %t, got %t", c.expected, got)
+			}
+		})
+	}
+
+	t.Run("Success_Audit", func(t *testing.T) {
+		log.SetLevel(logger.LevelError)
+		buf.Reset()
+
+		log.Audit(context.Background(), "log level changed")
+
+		if !strings.Contains(buf.String(), `"audit":true`) {
+			t.Errorf("Should log audit entries regardless of level, got %q", buf.String())
+		}
+	})
+
+	t.Run("Success_Expired", func(t *testing.T) {
+		log.Elevate(logger.ScopeUser, "user-1", time.Nanosecond)
+		time.Sleep(time.Millisecond)
+
+		if n := len(log.Elevations()); n != 0 {
+			t.Errorf("Should drop expired elevations, got %d", n)
+		}
+	})
+}
@@ -2,10 +2,12 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"runtime"
 	"time"
@@ -18,16 +20,33 @@ type TraceIDFn func(ctx context.Context) string
 type Logger struct {
 	handler   slog.Handler
 	traceIDFn TraceIDFn
+	levels    *levels
+}
+
+// Options represents optional behavior of a logger
+// Format is FormatJSON or FormatConsole and defaults to JSON
+// A nil RedactKeys uses DefaultRedactKeys while an empty slice disables key redaction
+// The zero Sampling writes every record
+type Options struct {
+	Events     Events
+	RedactKeys []string
+	Format     string
+	Sampling   Sampling
 }
 
 // New constructs log for app use
 func New(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn) *Logger {
-	return new(w, minLevel, serviceName, traceIDFn, Events{})
+	return new(w, minLevel, serviceName, traceIDFn, Options{})
 }
 
 // NewWithEvents constructs log for app use with events
 func NewWithEvents(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, events Events) *Logger {
-	return new(w, minLevel, serviceName, traceIDFn, events)
+	return new(w, minLevel, serviceName, traceIDFn, Options{Events: events})
+}
+
+// NewWithOptions constructs log for app use with optional behavior
+func NewWithOptions(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, opts Options) *Logger {
+	return new(w, minLevel, serviceName, traceIDFn, opts)
 }
 
 // NewWithHandler constructs log for app use with underlying handler
@@ -80,14 +99,81 @@ func (log *Logger) Errorc(ctx context.Context, caller int, msg string, args ...a
 	log.write(ctx, LevelError, caller, msg, args...)
 }
 
-// write prints a log to the stream
-func (log *Logger) write(ctx context.Context, level Level, caller int, msg string, args ...any) {
-	slogLevel := slog.Level(level)
+// Audit logs at LevelInfo regardless of the minimum level so changes to the service are always recorded
+func (log *Logger) Audit(ctx context.Context, msg string, args ...any) {
+	log.record(ctx, LevelInfo, 3, msg, append(args, "audit", true)...)
+}
+
+// SetLevel changes the minimum level that is logged
+func (log *Logger) SetLevel(level Level) {
+	if log.levels == nil {
+		return
+	}
+	log.levels.min.Set(slog.Level(level))
+}
+
+// Level returns the minimum level that is logged
+func (log *Logger) Level() Level {
+	if log.levels == nil {
+		return LevelInfo
+	}
+	return Level(log.levels.min.Level())
+}
+
+// SetUserIDFn sets the function used to find the user of a request for per-user elevations
+func (log *Logger) SetUserIDFn(fn UserIDFn) {
+	if log.levels == nil {
+		return
+	}
+	log.levels.userIDFn.Store(&fn)
+}
+
+// Elevate logs debug records for one trace or user until the duration passes
+func (log *Logger) Elevate(scope string, id string, d time.Duration) (Elevation, error) {
+	if log.levels == nil {
+		return Elevation{}, errors.New("logger does not support elevation")
+	}
+
+	if scope != ScopeTrace && scope != ScopeUser {
+		return Elevation{}, fmt.Errorf("unknown elevation scope %q", scope)
+	}
 
-	if !log.handler.Enabled(ctx, slogLevel) {
+	if id == "" || d <= 0 {
+		return Elevation{}, errors.New("elevation requires an id and a positive duration")
+	}
+
+	return log.levels.elevate(scope, id, d), nil
+}
+
+// Revoke ends the elevation of a trace or user early
+func (log *Logger) Revoke(scope string, id string) bool {
+	if log.levels == nil {
+		return false
+	}
+	return log.levels.revoke(scope, id)
+}
+
+// Elevations returns the elevations that have not expired
+func (log *Logger) Elevations() []Elevation {
+	if log.levels == nil {
+		return nil
+	}
+	return log.levels.list()
+}
+
+// write prints a log to the stream if the level is enabled
+func (log *Logger) write(ctx context.Context, level Level, caller int, msg string, args ...any) {
+	if !log.handler.Enabled(ctx, slog.Level(level)) {
 		return
 	}
 
+	log.record(ctx, level, caller+1, msg, args...)
+}
+
+// record prints a log to the stream
+func (log *Logger) record(ctx context.Context, level Level, caller int, msg string, args ...any) {
+	slogLevel := slog.Level(level)
+
 	var pcs [1]uintptr
 	runtime.Callers(caller, pcs[:])
 
@@ -102,8 +188,16 @@ func (log *Logger) write(ctx context.Context, level Level, caller int, msg strin
 }
 
 // new creates a new logger
-func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, events Events) *Logger {
-	// Convert filename to name.ext
+func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, opts Options) *Logger {
+	events := opts.Events
+
+	redactKeys := opts.RedactKeys
+	if redactKeys == nil {
+		redactKeys = DefaultRedactKeys
+	}
+	rd := newRedactor(redactKeys)
+
+	// Convert filename to name.ext and redact sensitive values
 	f := func(groups []string, a slog.Attr) slog.Attr {
 		if a.Key == slog.SourceKey {
 			if source, ok := a.Value.Any().(*slog.Source); ok {
@@ -112,15 +206,30 @@ func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, e
 			}
 		}
 
-		return a
+		return rd.attr(a)
+	}
+
+	// Construct the output handler gated by levels that can change at runtime
+	var handler slog.Handler
+	switch opts.Format {
+	case FormatConsole:
+		handler = newConsoleHandler(w, os.Getenv("NO_COLOR") == "", rd)
+	default:
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: slog.LevelDebug, ReplaceAttr: f})
 	}
 
-	// Construct slog JSON handler
-	handler := slog.Handler(slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: slog.Level(minLevel), ReplaceAttr: f}))
+	lvls := newLevels(minLevel, traceIDFn)
+	handler = &levelHandler{handler: handler, levels: lvls}
+
+	// Drop repeated and sampled out records before they reach the output
+	if opts.Sampling.enabled() {
+		handler = newSampleHandler(handler, opts.Sampling, traceIDFn)
+	}
 
 	// If events are to be processed, wrap JSON handler around custom handler
+	// Events see every record so consumers such as error tracking count the repeats sampling drops
 	if events.Debug != nil || events.Info != nil || events.Warn != nil || events.Error != nil {
-		handler = newLogHandler(handler, events)
+		handler = newLogHandler(handler, events, rd)
 	}
 
 	// Attributes to add to every log
@@ -134,5 +243,6 @@ func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, e
 	return &Logger{
 		handler:   handler,
 		traceIDFn: traceIDFn,
+		levels:    lvls,
 	}
 }
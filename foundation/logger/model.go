@@ -2,7 +2,10 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"path/filepath"
+	"runtime"
 	"time"
 )
 
@@ -22,23 +25,33 @@ type Record struct {
 	Time       time.Time
 	Message    string
 	Level      Level
+	File       string
 	Attributes map[string]any
 }
 
-// toRecord converts a slog record to our record
-func toRecord(r slog.Record) Record {
+// toRecord converts a slog record to our record, redacting sensitive values
+func toRecord(r slog.Record, rd *redactor) Record {
 	atts := make(map[string]any, r.NumAttrs())
 
 	f := func(attr slog.Attr) bool {
-		atts[attr.Key] = attr.Value.Any()
+		attr.Value = attr.Value.Resolve()
+		atts[attr.Key] = rd.attr(attr).Value.Any()
 		return true
 	}
 	r.Attrs(f)
 
+	var file string
+	if r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		file = fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+	}
+
 	return Record{
 		Time:       r.Time,
 		Message:    r.Message,
 		Level:      Level(r.Level),
+		File:       file,
 		Attributes: atts,
 	}
 }
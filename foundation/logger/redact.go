@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces every value that must not be logged
+const redacted = "[REDACTED]"
+
+// DefaultRedactKeys are the patterns of attribute keys and query parameters whose values are redacted
+var DefaultRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "cookie", "api_key", "apikey", "email"}
+
+// Secret represents a value that is always logged as [REDACTED]
+type Secret string
+
+// LogValue hides the secret from slog handlers
+func (Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// String hides the secret when formatted with fmt
+func (Secret) String() string {
+	return redacted
+}
+
+// GoString hides the secret when formatted with %#v
+func (Secret) GoString() string {
+	return redacted
+}
+
+// MarshalText hides the secret when encoded
+func (Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// redactor removes sensitive values from attributes before they are written
+type redactor struct {
+	keys []string
+}
+
+// newRedactor constructs a redactor that matches keys containing any of the patterns, ignoring case
+func newRedactor(patterns []string) *redactor {
+	keys := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			keys = append(keys, p)
+		}
+	}
+
+	return &redactor{keys: keys}
+}
+
+// attr redacts the value of a sensitive key and scrubs sensitive query parameters from string values
+func (rd *redactor) attr(a slog.Attr) slog.Attr {
+	if rd == nil || len(rd.keys) == 0 {
+		return a
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+
+	if rd.sensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	if a.Value.Kind() == slog.KindString {
+		if s, ok := rd.scrubQuery(a.Value.String()); ok {
+			return slog.String(a.Key, s)
+		}
+	}
+
+	return a
+}
+
+// sensitive checks if a key matches one of the patterns
+func (rd *redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range rd.keys {
+		if strings.Contains(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrubQuery redacts sensitive parameters of the query string in a path or URL
+// It reports false when the value has no query string or nothing was redacted
+func (rd *redactor) scrubQuery(s string) (string, bool) {
+	base, rawQuery, found := strings.Cut(s, "?")
+	if !found || !strings.Contains(rawQuery, "=") {
+		return s, false
+	}
+
+	fragment := ""
+	if i := strings.IndexByte(rawQuery, '#'); i >= 0 {
+		rawQuery, fragment = rawQuery[:i], rawQuery[i:]
+	}
+
+	params := strings.Split(rawQuery, "&")
+
+	var changed bool
+	for i, param := range params {
+		name, _, found := strings.Cut(param, "=")
+		if !found {
+			continue
+		}
+
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+
+		if rd.sensitive(name) {
+			params[i] = url.QueryEscape(name) + "=" + url.QueryEscape(redacted)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return s, false
+	}
+
+	return base + "?" + strings.Join(params, "&") + fragment, true
+}
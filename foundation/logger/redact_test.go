@@ -0,0 +1,61 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_Redact(t *testing.T) {
+	var buf bytes.Buffer
+	var event logger.Record
+
+	events := logger.Events{
+		Info: func(ctx context.Context, r logger.Record) { event = r },
+	}
+	log := logger.NewWithEvents(&buf, logger.LevelInfo, "TEST", nil, events)
+
+	cases := []struct {
+		name     string
+		key      string
+		value    any
+		expected string
+	}{
+		{name: "Success_Password", key: "password", value: "hunter2", expected: "[REDACTED]"},
+		{name: "Success_KeyCase", key: "Authorization", value: "Bearer abc", expected: "[REDACTED]"},
+		{name: "Success_KeySubstring", key: "user_email", value: "a@b.com", expected: "[REDACTED]"},
+		{name: "Success_Secret", key: "dsn", value: logger.Secret("postgres://user:pass@db"), expected: "[REDACTED]"},
+		{name: "Success_Query", key: "path", value: "/reviews/1/discussion?access_token=abc&page=2", expected: "/reviews/1/discussion?access_token=%5BREDACTED%5D&page=2"},
+		{name: "Success_QueryUntouched", key: "path", value: "/reviews?page=2", expected: "/reviews?page=2"},
+		{name: "Success_Plain", key: "method", value: "GET", expected: "GET"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+
+			log.Info(context.Background(), "redaction", c.key, c.value)
+
+			var out map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+				t.Fatalf("Should log JSON: %s", err)
+			}
+
+			if got := out[c.key]; got != c.expected {
+				t.Errorf("Should log %s as %q, got %q", c.key, c.expected, got)
+			}
+
+			if got := fmt.Sprint(event.Attributes[c.key]); got != c.expected {
+				t.Errorf("Should pass %s to events as %q, got %q", c.key, c.expected, got)
+			}
+		})
+	}
+
+	if got := fmt.Sprintf("%v %s %#v", logger.Secret("x"), logger.Secret("x"), logger.Secret("x")); got != "[REDACTED] [REDACTED] [REDACTED]" {
+		t.Errorf("Should format secrets as redacted, got %q", got)
+	}
+}
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSampleEntries bounds the number of distinct messages tracked for deduplication
+// Messages beyond the bound are always written so a new error is never lost
+const maxSampleEntries = 1024
+
+// fingerprintKeys are the attributes that, with the level and message, identify repeated records
+// A component or reason tells apart records that share a generic message such as a failing health check
+var fingerprintKeys = []string{"ERROR", "error", "status", "component", "reason"}
+
+// Sampling represents how repeated and high volume records are reduced
+// Warn and Error records with the same message within Window are written once, followed by a summary of how many were suppressed
+// Info records of a request are kept at InfoRate, decided once per trace so a request's records are kept or dropped together
+// A zero Window disables deduplication and a rate of zero or one keeps every Info record
+// Sampling only reduces the output and events still receive every record
+type Sampling struct {
+	Window   time.Duration
+	InfoRate float64
+}
+
+// enabled checks if the sampling drops any records
+func (s Sampling) enabled() bool {
+	return s.Window > 0 || (s.InfoRate > 0 && s.InfoRate < 1)
+}
+
+// sampleEntry tracks a message seen within the current window
+type sampleEntry struct {
+	handler    slog.Handler
+	first      slog.Record
+	start      time.Time
+	suppressed int
+}
+
+// sampleState is shared by a sampleHandler and the handlers derived from it
+type sampleState struct {
+	sampling  Sampling
+	traceIDFn TraceIDFn
+
+	mu        sync.Mutex
+	entries   map[string]*sampleEntry
+	lastSweep time.Time
+}
+
+// sampleHandler drops repeated and sampled out records before they reach the wrapped handler
+type sampleHandler struct {
+	handler slog.Handler
+	state   *sampleState
+}
+
+// newSampleHandler constructs a handler that applies the sampling to records
+func newSampleHandler(handler slog.Handler, sampling Sampling, traceIDFn TraceIDFn) *sampleHandler {
+	state := sampleState{
+		sampling:  sampling,
+		traceIDFn: traceIDFn,
+		entries:   make(map[string]*sampleEntry),
+		lastSweep: time.Now(),
+	}
+
+	return &sampleHandler{handler: handler, state: &state}
+}
+
+// Enabled reports whether the wrapped handler handles records at the level
+func (h *sampleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a sampleHandler sharing the sampling state whose wrapped handler has the attrs
+func (h *sampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sampleHandler{handler: h.handler.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup returns a sampleHandler sharing the sampling state whose wrapped handler has the group
+func (h *sampleHandler) WithGroup(name string) slog.Handler {
+	return &sampleHandler{handler: h.handler.WithGroup(name), state: h.state}
+}
+
+// Handle writes the record unless it is a repeat within the window or its trace is sampled out
+// Summaries of windows that have ended are written first
+func (h *sampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level == slog.LevelInfo && !h.state.keepInfo(ctx, r) {
+		return nil
+	}
+
+	if r.Level < slog.LevelWarn || h.state.sampling.Window <= 0 {
+		return h.handler.Handle(ctx, r)
+	}
+
+	keep, summaries := h.state.dedup(h.handler, r)
+	for _, s := range summaries {
+		s.handler.Handle(ctx, s.summary())
+	}
+
+	if !keep {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// keepInfo decides if an Info record is written based on the trace of the request
+// Audit records and records outside of a request are always kept
+func (s *sampleState) keepInfo(ctx context.Context, r slog.Record) bool {
+	rate := s.sampling.InfoRate
+	if rate <= 0 || rate >= 1 || s.traceIDFn == nil {
+		return true
+	}
+
+	audit := false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "audit" {
+			audit = true
+			return false
+		}
+		return true
+	})
+	if audit {
+		return true
+	}
+
+	traceID := s.traceIDFn(ctx)
+	if strings.Trim(traceID, "0") == "" {
+		return true
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(traceID))
+
+	return float64(hash.Sum64()) < rate*math.MaxUint64
+}
+
+// dedup reports if the record is the first of its kind in the window
+// It also returns the entries of ended windows that suppressed records so their summaries can be written
+func (s *sampleState) dedup(handler slog.Handler, r slog.Record) (bool, []sampleEntry) {
+	key := fingerprint(r)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []sampleEntry
+
+	if now.Sub(s.lastSweep) >= s.sampling.Window {
+		for k, e := range s.entries {
+			if now.Sub(e.start) < s.sampling.Window {
+				continue
+			}
+			if e.suppressed > 0 {
+				summaries = append(summaries, *e)
+			}
+			delete(s.entries, k)
+		}
+		s.lastSweep = now
+	}
+
+	if e, exists := s.entries[key]; exists {
+		if now.Sub(e.start) < s.sampling.Window {
+			e.suppressed++
+			return false, summaries
+		}
+
+		if e.suppressed > 0 {
+			summaries = append(summaries, *e)
+		}
+		delete(s.entries, key)
+	}
+
+	if len(s.entries) < maxSampleEntries {
+		s.entries[key] = &sampleEntry{
+			handler: handler,
+			first:   r.Clone(),
+			start:   now,
+		}
+	}
+
+	return true, summaries
+}
+
+// summary builds the record reporting how many repeats of the entry's message were suppressed
+func (e sampleEntry) summary() slog.Record {
+	r := slog.NewRecord(time.Now(), e.first.Level, "suppressed repeated log", e.first.PC)
+	r.AddAttrs(
+		slog.String("message", e.first.Message),
+		slog.Int("suppressed", e.suppressed),
+		slog.String("window", time.Since(e.start).Round(time.Millisecond).String()),
+	)
+
+	e.first.Attrs(func(a slog.Attr) bool {
+		for _, k := range fingerprintKeys {
+			if a.Key == k {
+				r.AddAttrs(a)
+			}
+		}
+		return true
+	})
+
+	return r
+}
+
+// fingerprint identifies records that repeat the same message and error
+func fingerprint(r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte('|')
+	b.WriteString(r.Message)
+
+	r.Attrs(func(a slog.Attr) bool {
+		for _, k := range fingerprintKeys {
+			if a.Key == k {
+				b.WriteByte('|')
+				b.WriteString(a.Key)
+				b.WriteByte('=')
+				b.WriteString(a.Value.String())
+			}
+		}
+		return true
+	})
+
+	return b.String()
+}
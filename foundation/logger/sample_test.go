@@ -0,0 +1,114 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/logger"
+)
+
+func Test_SamplingDedup(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := logger.NewWithOptions(&buf, logger.LevelInfo, "TEST", nil, logger.Options{
+		Sampling: logger.Sampling{Window: 50 * time.Millisecond},
+	})
+
+	ctx := context.Background()
+
+	for range 5 {
+		log.Error(ctx, "message", "ERROR", "db is down")
+	}
+	log.Error(ctx, "message", "ERROR", "db is on fire")
+
+	if n := strings.Count(buf.String(), `"ERROR":"db is down"`); n != 1 {
+		t.Errorf("Should write a repeated error once within the window, got %d", n)
+	}
+	if !strings.Contains(buf.String(), "db is on fire") {
+		t.Errorf("Should never drop an error with a new message")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	buf.Reset()
+
+	log.Error(ctx, "message", "ERROR", "db is down")
+
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"suppressed repeated log"`) || !strings.Contains(out, `"suppressed":4`) {
+		t.Errorf("Should summarize the suppressed records once the window ends, got %s", out)
+	}
+	if n := strings.Count(out, `"ERROR":"db is down"`); n != 2 {
+		t.Errorf("Should write the summary and the record of the new window, got %d", n)
+	}
+}
+
+func Test_SamplingDedup_Components(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := logger.NewWithOptions(&buf, logger.LevelInfo, "TEST", nil, logger.Options{
+		Sampling: logger.Sampling{Window: time.Hour},
+	})
+
+	ctx := context.Background()
+
+	for range 3 {
+		log.Warn(ctx, "readiness failure", "component", "database", "reason", "connection refused")
+		log.Warn(ctx, "readiness failure", "component", "keystore", "reason", "connection refused")
+	}
+
+	out := buf.String()
+	for _, component := range []string{"database", "keystore"} {
+		if n := strings.Count(out, `"component":"`+component+`"`); n != 1 {
+			t.Errorf("Should write the failure of component %s once, got %d", component, n)
+		}
+	}
+}
+
+func Test_SamplingInfo(t *testing.T) {
+	var buf bytes.Buffer
+
+	traceIDFn := func(ctx context.Context) string {
+		id, _ := ctx.Value(traceKey{}).(string)
+		return id
+	}
+	log := logger.NewWithOptions(&buf, logger.LevelInfo, "TEST", traceIDFn, logger.Options{
+		Sampling: logger.Sampling{InfoRate: 0.5},
+	})
+
+	var kept int
+	for i := range 1000 {
+		buf.Reset()
+
+		ctx := context.WithValue(context.Background(), traceKey{}, fmt.Sprintf("trace-%d", i))
+		log.Info(ctx, "request started")
+		log.Info(ctx, "request completed")
+		log.Audit(ctx, "audit record")
+		log.Error(ctx, "error record")
+
+		out := buf.String()
+		started := strings.Contains(out, "request started")
+		if started != strings.Contains(out, "request completed") {
+			t.Fatalf("Should keep or drop every record of a trace together, got %s", out)
+		}
+		if !strings.Contains(out, "audit record") || !strings.Contains(out, "error record") {
+			t.Fatalf("Should never sample audit or error records, got %s", out)
+		}
+		if started {
+			kept++
+		}
+	}
+
+	if kept < 400 || kept > 600 {
+		t.Errorf("Should keep about half of the traces, got %d of 1000", kept)
+	}
+
+	buf.Reset()
+	log.Info(context.Background(), "startup")
+	if !strings.Contains(buf.String(), "startup") {
+		t.Errorf("Should keep records outside of a request")
+	}
+}
@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+// Version is the OpenAPI specification version documents are generated for
+const Version = "3.1.0"
+
+// securityScheme is the name of the bearer token security scheme
+const securityScheme = "bearerAuth"
+
+// Document represents an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info represents the metadata of the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem represents the operations available on a path keyed by lowercase method
+type PathItem map[string]*Operation
+
+// Operation represents a single method on a path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter represents a path parameter of an operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody represents the payload accepted by an operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response represents a possible response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType represents the schema of a payload
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas and security schemes of the document
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme represents a way to authenticate with the API
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Config represents the information needed to generate a document
+// ErrorType holds a zero value of the type errors are responded with
+type Config struct {
+	Title       string
+	Version     string
+	Description string
+	ErrorType   any
+}
+
+// pathParam matches the wildcards of a route path
+var pathParam = regexp.MustCompile(`\{([^}.$]+)(\.\.\.)?\}`)
+
+// Generate builds an OpenAPI document from the routes registered on an app
+func Generate(cfg Config, routes []web.Route) Document {
+	g := newGenerator()
+
+	doc := Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       cfg.Title,
+			Version:     cfg.Version,
+			Description: cfg.Description,
+		},
+		Paths: make(map[string]PathItem),
+	}
+
+	var errorSchema *Schema
+	if cfg.ErrorType != nil {
+		errorSchema = g.schemaOf(cfg.ErrorType)
+	}
+
+	var secured bool
+
+	for _, route := range routes {
+		method := route.Method()
+		if method == "" {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path(), "{$1}")
+		path = strings.TrimSuffix(path, "{$}")
+
+		op := operation(g, route, method, path, errorSchema)
+		if route.Doc.Auth {
+			secured = true
+		}
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(method)] = op
+	}
+
+	doc.Components.Schemas = g.schemas
+
+	if secured {
+		doc.Components.SecuritySchemes = map[string]SecurityScheme{
+			securityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+	}
+
+	return doc
+}
+
+// operation builds the operation for a route
+func operation(g *generator, route web.Route, method string, path string, errorSchema *Schema) *Operation {
+	d := route.Doc
+
+	op := Operation{
+		OperationID: operationID(method, path),
+		Summary:     d.Summary,
+		Description: d.Description,
+		Tags:        d.Tags,
+		Responses:   make(map[string]Response),
+		Deprecated:  d.Deprecated,
+	}
+
+	if len(d.Roles) > 0 {
+		op.Description = strings.TrimSpace(op.Description + "\n\nRequires roles: " + strings.Join(d.Roles, ", "))
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(route.Path(), -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if d.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: g.schemaOf(d.Request)}},
+		}
+	}
+
+	status := d.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	success := Response{Description: http.StatusText(status)}
+	if d.Response != nil && status != http.StatusNoContent {
+		success.Content = map[string]MediaType{"application/json": {Schema: g.schemaOf(d.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	codes := make(map[int][]string)
+	for _, e := range d.Errors {
+		codes[e.Status] = append(codes[e.Status], e.Code)
+	}
+
+	for status, names := range codes {
+		sort.Strings(names)
+
+		resp := Response{Description: strings.Join(names, ", ")}
+		if errorSchema != nil {
+			resp.Content = map[string]MediaType{"application/json": {Schema: errorSchema}}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+
+	if d.Auth {
+		op.Security = []map[string][]string{{securityScheme: {}}}
+	}
+
+	return &op
+}
+
+// operationID derives a unique identifier for an operation from its method and path
+func operationID(method string, path string) string {
+	parts := []string{strings.ToLower(method)}
+
+	for _, segment := range strings.Split(path, "/") {
+		segment = invalidName.ReplaceAllString(strings.Trim(segment, "{}"), "_")
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+
+	return strings.Join(parts, "_")
+}
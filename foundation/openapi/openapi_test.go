@@ -0,0 +1,109 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/openapi"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+	"github.com/google/uuid"
+)
+
+type audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type review struct {
+	audit
+	ID      uuid.UUID `json:"id"`
+	Score   int       `json:"score"`
+	Body    string    `json:"body,omitempty"`
+	Tags    []string  `json:"tags"`
+	Replies []review  `json:"replies"`
+	secret  string
+}
+
+type failure struct {
+	Message string `json:"message"`
+}
+
+func Test_Generate(t *testing.T) {
+	app := web.NewApp(nil)
+	app.Handle("PUT /v1/reviews/{id}", nil).Describe(web.RouteDoc{
+		Summary:  "Update a review",
+		Request:  review{},
+		Response: review{},
+		Auth:     true,
+		Errors:   []web.ErrorDoc{{Status: http.StatusNotFound, Code: "not_found"}, {Status: http.StatusPreconditionFailed, Code: "failed_precondition"}},
+	})
+	app.Handle("GET /v1/files/{path...}", nil)
+
+	doc := openapi.Generate(openapi.Config{Title: "Test", Version: "v1", ErrorType: failure{}}, app.Routes())
+
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("Should generate version %s, got %s", openapi.Version, doc.OpenAPI)
+	}
+
+	op := doc.Paths["/v1/reviews/{id}"]["put"]
+	if op == nil {
+		t.Fatalf("Should document PUT /v1/reviews/{id}, got paths %v", doc.Paths)
+	}
+
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" {
+		t.Errorf("Should document the id path parameter, got %+v", op.Parameters)
+	}
+
+	for _, status := range []string{"200", "404", "412"} {
+		if _, ok := op.Responses[status]; !ok {
+			t.Errorf("Should document the %s response", status)
+		}
+	}
+
+	if len(op.Security) != 1 || doc.Components.SecuritySchemes["bearerAuth"].Scheme != "bearer" {
+		t.Errorf("Should require bearer authentication")
+	}
+
+	if op.RequestBody == nil || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/review" {
+		t.Fatalf("Should reference the review schema in the request body")
+	}
+
+	s := doc.Components.Schemas["review"]
+	if s == nil {
+		t.Fatalf("Should register the review schema")
+	}
+
+	expected := map[string]string{"created_at": "string", "id": "string", "score": "integer", "body": "string", "tags": "array", "replies": "array"}
+	for name, typ := range expected {
+		prop, ok := s.Properties[name]
+		if !ok {
+			t.Errorf("Should document the %s property", name)
+			continue
+		}
+		if prop.Type != typ {
+			t.Errorf("Should document %s as %s, got %s", name, typ, prop.Type)
+		}
+	}
+
+	if _, ok := s.Properties["secret"]; ok {
+		t.Errorf("Should not document unexported fields")
+	}
+
+	if s.Properties["id"].Format != "uuid" || s.Properties["created_at"].Format != "date-time" {
+		t.Errorf("Should document uuid and time formats")
+	}
+
+	if s.Properties["replies"].Items.Ref != "#/components/schemas/review" {
+		t.Errorf("Should reference recursive types, got %+v", s.Properties["replies"].Items)
+	}
+
+	for _, name := range s.Required {
+		if name == "body" {
+			t.Errorf("Should not require omitempty fields")
+		}
+	}
+
+	if _, ok := doc.Paths["/v1/files/{path}"]["get"]; !ok {
+		t.Errorf("Should normalize wildcard path parameters, got paths %v", doc.Paths)
+	}
+}
@@ -0,0 +1,248 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// scopeName identifies the instrumentation that produced the spans
+const scopeName = "github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+
+// FileExporter writes each batch of spans as one line of OTLP/JSON
+// The output can be replayed into a collector with its file receiver
+type FileExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewFileExporter constructs an exporter that appends to the file at path
+func NewFileExporter(path string) (*FileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating trace directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening trace file: %w", err)
+	}
+
+	return &FileExporter{w: f}, nil
+}
+
+// NewWriterExporter constructs an exporter that writes to w
+func NewWriterExporter(w io.Writer) *FileExporter {
+	return &FileExporter{w: w}
+}
+
+// Export writes the spans as a single OTLP/JSON export request
+func (e *FileExporter) Export(ctx context.Context, resource Resource, spans []SpanData) error {
+	data, err := json.Marshal(newExportRequest(resource, spans))
+	if err != nil {
+		return fmt.Errorf("encoding spans: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := e.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing spans: %w", err)
+	}
+
+	return nil
+}
+
+// HTTPExporter posts spans to the OTLP/HTTP traces endpoint of a collector using the JSON encoding
+type HTTPExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPExporter constructs an exporter that posts to an endpoint such as http://localhost:4318/v1/traces
+func NewHTTPExporter(endpoint string, timeout time.Duration) *HTTPExporter {
+	return &HTTPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Export posts the spans as a single OTLP/JSON export request
+func (e *HTTPExporter) Export(ctx context.Context, resource Resource, spans []SpanData) error {
+	data, err := json.Marshal(newExportRequest(resource, spans))
+	if err != nil {
+		return fmt.Errorf("encoding spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("exporting spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("exporting spans: collector responded with %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// =============================================================================
+// OTLP/JSON encoding of ExportTraceServiceRequest
+// Ids are hex strings and 64 bit integers are strings as the JSON mapping of the protobuf requires
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   otlpResource `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              Kind       `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            otlpStatus `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// newExportRequest converts finished spans to their OTLP/JSON representation
+func newExportRequest(resource Resource, spans []SpanData) exportRequest {
+	attrs := []keyValue{newKeyValue("service.name", resource.ServiceName)}
+	if resource.ServiceVersion != "" {
+		attrs = append(attrs, newKeyValue("service.version", resource.ServiceVersion))
+	}
+
+	out := make([]otlpSpan, len(spans))
+	for i, s := range spans {
+		kvs := make([]keyValue, len(s.Attrs))
+		for j, a := range s.Attrs {
+			kvs[j] = newKeyValue(a.Key, a.Value)
+		}
+
+		out[i] = otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentSpanID,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        kvs,
+			Status:            otlpStatus{Code: s.Status, Message: s.StatusMessage},
+		}
+	}
+
+	req := exportRequest{
+		ResourceSpans: []resourceSpans{
+			{
+				Resource: otlpResource{Attributes: attrs},
+				ScopeSpans: []scopeSpans{
+					{
+						Scope: scope{Name: scopeName},
+						Spans: out,
+					},
+				},
+			},
+		},
+	}
+
+	return req
+}
+
+// newKeyValue converts an attribute to its OTLP representation, formatting unknown types as strings
+func newKeyValue(key string, value any) keyValue {
+	var v anyValue
+
+	switch val := value.(type) {
+	case string:
+		v.StringValue = &val
+	case bool:
+		v.BoolValue = &val
+	case int:
+		v.IntValue = formatInt(int64(val))
+	case int32:
+		v.IntValue = formatInt(int64(val))
+	case int64:
+		v.IntValue = formatInt(val)
+	case uint32:
+		v.IntValue = formatInt(int64(val))
+	case uint64:
+		if val > math.MaxInt64 {
+			s := strconv.FormatUint(val, 10)
+			v.StringValue = &s
+			break
+		}
+		v.IntValue = formatInt(int64(val))
+	case float64:
+		v.DoubleValue = &val
+	case float32:
+		f := float64(val)
+		v.DoubleValue = &f
+	case time.Duration:
+		v.IntValue = formatInt(int64(val))
+	default:
+		s := fmt.Sprint(val)
+		v.StringValue = &s
+	}
+
+	return keyValue{Key: key, Value: v}
+}
+
+// formatInt formats an integer as the string OTLP/JSON uses for 64 bit values
+func formatInt(n int64) *string {
+	s := strconv.FormatInt(n, 10)
+	return &s
+}
@@ -0,0 +1,341 @@
+// Package tracer records the spans of a request and exports them in the OTLP/JSON format
+package tracer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults used when the configuration leaves a setting empty
+const (
+	defaultQueueSize     = 2048
+	defaultBatchSize     = 512
+	defaultFlushInterval = 5 * time.Second
+)
+
+// Exporter sends finished spans to a trace backend
+type Exporter interface {
+	Export(ctx context.Context, resource Resource, spans []SpanData) error
+}
+
+// Resource describes the service that produced the spans
+type Resource struct {
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Config represents the configuration of a tracer
+// Probability is the share of new traces that are recorded, between 0 and 1
+type Config struct {
+	Resource      Resource
+	Exporter      Exporter
+	Probability   float64
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	ErrorFn       func(err error)
+}
+
+// Tracer starts spans and exports them in batches once they end
+type Tracer struct {
+	resource  Resource
+	exporter  Exporter
+	threshold uint64
+	queue     chan SpanData
+	batchSize int
+	interval  time.Duration
+	errorFn   func(err error)
+	dropped   atomic.Int64
+	mu        sync.Mutex
+	exportErr error
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// New constructs a tracer and starts exporting spans in the background
+func New(cfg Config) (*Tracer, error) {
+	if cfg.Exporter == nil {
+		return nil, errors.New("tracer requires an exporter")
+	}
+
+	if cfg.Probability < 0 || cfg.Probability > 1 {
+		return nil, errors.New("trace probability must be between 0 and 1")
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.ErrorFn == nil {
+		cfg.ErrorFn = func(error) {}
+	}
+
+	t := Tracer{
+		resource:  cfg.Resource,
+		exporter:  cfg.Exporter,
+		threshold: threshold(cfg.Probability),
+		queue:     make(chan SpanData, cfg.QueueSize),
+		batchSize: cfg.BatchSize,
+		interval:  cfg.FlushInterval,
+		errorFn:   cfg.ErrorFn,
+		done:      make(chan struct{}),
+	}
+
+	t.wg.Add(1)
+	go t.run()
+
+	return &t, nil
+}
+
+// Shutdown stops accepting spans and exports the ones that are queued
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.closeOnce.Do(func() {
+		close(t.done)
+	})
+
+	ch := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(ch)
+	}()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Check reports the error of the last export so a health check can show the exporter is failing
+// A nil tracer is healthy since tracing is disabled
+func (t *Tracer) Check(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	select {
+	case <-t.done:
+		return errors.New("tracer is shut down")
+	default:
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.exportErr != nil {
+		return fmt.Errorf("exporting spans: %w", t.exportErr)
+	}
+
+	return nil
+}
+
+// Dropped returns how many spans were discarded because the export queue was full
+func (t *Tracer) Dropped() int64 {
+	if t == nil {
+		return 0
+	}
+	return t.dropped.Load()
+}
+
+// Sample decides if a new trace is recorded based on its id so every service makes the same decision
+func (t *Tracer) Sample(traceID string) bool {
+	if t == nil || len(traceID) != 32 {
+		return false
+	}
+
+	n, err := strconv.ParseUint(traceID[16:], 16, 64)
+	if err != nil {
+		return false
+	}
+
+	return n>>1 < t.threshold
+}
+
+// StartServer starts the root span of a request that continues the trace of its caller
+// The span is only recorded when the caller's trace context is sampled
+func (t *Tracer) StartServer(ctx context.Context, name string, sc SpanContext) (context.Context, *Span) {
+	if t == nil || !sc.Sampled {
+		return ctx, nil
+	}
+
+	s := Span{
+		tracer: t,
+		data: SpanData{
+			TraceID:      sc.TraceID,
+			SpanID:       sc.SpanID,
+			ParentSpanID: sc.ParentSpanID,
+			Name:         name,
+			Kind:         KindServer,
+			Start:        time.Now(),
+		},
+	}
+
+	return context.WithValue(ctx, spanKey, &s), &s
+}
+
+// Start starts a child of the span in the context
+// It returns a nil span, which is safe to use, when the context is not being traced
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return start(ctx, name, KindInternal)
+}
+
+// StartClient starts a child span that represents a call to another service
+func StartClient(ctx context.Context, name string) (context.Context, *Span) {
+	return start(ctx, name, KindClient)
+}
+
+// start starts a child of the span in the context with the given kind
+func start(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	parent := FromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+
+	s := Span{
+		tracer: parent.tracer,
+		data: SpanData{
+			TraceID:      parent.data.TraceID,
+			SpanID:       NewSpanID(),
+			ParentSpanID: parent.data.SpanID,
+			Name:         name,
+			Kind:         kind,
+			Start:        time.Now(),
+		},
+	}
+
+	return context.WithValue(ctx, spanKey, &s), &s
+}
+
+// enqueue hands a finished span to the exporter without blocking the request
+func (t *Tracer) enqueue(data SpanData) {
+	select {
+	case <-t.done:
+		t.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case t.queue <- data:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// run batches finished spans and exports them when the batch is full or the interval passes
+func (t *Tracer) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), t.interval)
+		defer cancel()
+
+		err := t.exporter.Export(ctx, t.resource, batch)
+		if err != nil {
+			t.errorFn(err)
+		}
+
+		t.mu.Lock()
+		t.exportErr = err
+		t.mu.Unlock()
+
+		batch = make([]SpanData, 0, t.batchSize)
+	}
+
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= t.batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+
+		case <-t.done:
+			for {
+				select {
+				case data := <-t.queue:
+					batch = append(batch, data)
+					if len(batch) >= t.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// threshold converts a probability to the largest sampled value of a 63 bit trace id fragment
+func threshold(probability float64) uint64 {
+	if probability >= 1 {
+		return math.MaxUint64
+	}
+	return uint64(probability * (1 << 63))
+}
+
+// =============================================================================
+
+type ctxKey int
+
+const spanKey ctxKey = 1
+
+// FromContext returns the active span of the context or nil if the context is not being traced
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey).(*Span)
+	return s
+}
+
+// NewTraceID generates a random trace id
+func NewTraceID() string {
+	return newID(16)
+}
+
+// NewSpanID generates a random span id
+func NewSpanID() string {
+	return newID(8)
+}
+
+// newID generates a random non-zero hex encoded id of n bytes
+func newID(n int) string {
+	b := make([]byte, n)
+	for {
+		rand.Read(b)
+		if binary.BigEndian.Uint64(b[n-8:]) != 0 {
+			return hex.EncodeToString(b)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package tracer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+type otlpSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         int    `json:"kind"`
+	Status       struct {
+		Code int `json:"code"`
+	} `json:"status"`
+}
+
+type exportRequest struct {
+	ResourceSpans []struct {
+		ScopeSpans []struct {
+			Spans []otlpSpan `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+func Test_Tracer(t *testing.T) {
+	var buf bytes.Buffer
+
+	trc, err := tracer.New(tracer.Config{
+		Resource:    tracer.Resource{ServiceName: "test"},
+		Exporter:    tracer.NewWriterExporter(&buf),
+		Probability: 1,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a tracer: %s", err)
+	}
+
+	app := web.NewApp(nil)
+	app.SetTracer(trc)
+	app.Handle("GET /reviews/{id}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, span := tracer.Start(ctx, "load review")
+		span.SetError(errors.New("not found"))
+		span.End()
+
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	})
+
+	const upstreamTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const upstreamSpan = "00f067aa0ba902b7"
+
+	r := httptest.NewRequest(http.MethodGet, "/reviews/1", nil)
+	r.Header.Set("traceparent", "00-"+upstreamTrace+"-"+upstreamSpan+"-01")
+	app.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest(http.MethodGet, "/reviews/2", nil)
+	r.Header.Set("traceparent", "00-"+upstreamTrace+"-"+upstreamSpan+"-00")
+	app.ServeHTTP(httptest.NewRecorder(), r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := trc.Shutdown(ctx); err != nil {
+		t.Fatalf("Should be able to shut down the tracer: %s", err)
+	}
+
+	var req exportRequest
+	if err := json.Unmarshal(buf.Bytes(), &req); err != nil {
+		t.Fatalf("Should export OTLP/JSON: %s: %s", err, buf.String())
+	}
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Should only record the sampled request, got %d spans", len(spans))
+	}
+
+	child, server := spans[0], spans[1]
+
+	if server.Name != "GET /reviews/{id}" || server.Kind != int(tracer.KindServer) {
+		t.Errorf("Should name the server span after the route, got %q kind %d", server.Name, server.Kind)
+	}
+
+	if server.TraceID != upstreamTrace || server.ParentSpanID != upstreamSpan {
+		t.Errorf("Should continue the upstream trace, got trace %s parent %s", server.TraceID, server.ParentSpanID)
+	}
+
+	if child.TraceID != upstreamTrace || child.ParentSpanID != server.SpanID {
+		t.Errorf("Should parent the child span to the server span, got parent %s", child.ParentSpanID)
+	}
+
+	if child.Status.Code != int(tracer.StatusError) {
+		t.Errorf("Should record the error status, got %d", child.Status.Code)
+	}
+}
+
+func Test_Sample(t *testing.T) {
+	cases := []struct {
+		name        string
+		probability float64
+		traceID     string
+		expected    bool
+	}{
+		{name: "Success_Always", probability: 1, traceID: "4bf92f3577b34da6ffffffffffffffff", expected: true},
+		{name: "Success_BelowThreshold", probability: 0.5, traceID: "4bf92f3577b34da60000000000000001", expected: true},
+		{name: "Fail_AboveThreshold", probability: 0.5, traceID: "4bf92f3577b34da6ffffffffffffffff", expected: false},
+		{name: "Fail_Never", probability: 0, traceID: "4bf92f3577b34da60000000000000001", expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			trc, err := tracer.New(tracer.Config{Exporter: tracer.NewWriterExporter(&bytes.Buffer{}), Probability: c.probability})
+			if err != nil {
+				t.Fatalf("Should be able to create a tracer: %s", err)
+			}
+			defer trc.Shutdown(context.Background())
+
+			if got := trc.Sample(c.traceID); got != c.expected {
+				t.Errorf("Should sample %t, got %t", c.expected, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"net/http"
 	"time"
 )
 
@@ -12,8 +13,14 @@ const key ctxKey = 1
 // Values represents information stored in the context of each web request
 type Values struct {
 	TraceID    string
+	SpanID     string
 	Now        time.Time
 	StatusCode int
+
+	trace   traceContext
+	method  string
+	header  http.Header
+	closing <-chan struct{}
 }
 
 // GetValues retrieves all information from the context
@@ -21,7 +28,7 @@ func GetValues(ctx context.Context) *Values {
 	v, ok := ctx.Value(key).(*Values)
 	if !ok {
 		return &Values{
-			TraceID: "00000000-0000-0000-0000-000000000000",
+			TraceID: zeroTraceID,
 			Now:     time.Now(),
 		}
 	}
@@ -33,7 +40,7 @@ func GetValues(ctx context.Context) *Values {
 func GetTraceID(ctx context.Context) string {
 	v, ok := ctx.Value(key).(*Values)
 	if !ok {
-		return "00000000-0000-0000-0000-000000000000"
+		return zeroTraceID
 	}
 	return v.TraceID
 }
@@ -47,6 +54,16 @@ func GetTime(ctx context.Context) time.Time {
 	return v.Now
 }
 
+// Closing returns a channel that is closed when the app is shutting down
+// Long-lived handlers such as streams should return once it is closed
+func Closing(ctx context.Context) <-chan struct{} {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return nil
+	}
+	return v.closing
+}
+
 // setStatusCode sets the StatusCode of the context
 func setStatusCode(ctx context.Context, statusCode int) {
 	v, ok := ctx.Value(key).(*Values)
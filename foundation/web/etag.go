@@ -0,0 +1,78 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrPreconditionFailed is returned when the If-Match header does not match the current representation
+var ErrPreconditionFailed = errors.New("resource has been modified")
+
+// ETag produces a strong entity tag from the encoded representation of a resource
+func ETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// VersionETag produces a strong entity tag from the version of a resource
+// Handlers can set it as the ETag header before calling Respond so it is used instead of hashing the body
+func VersionETag(version string) string {
+	return `"` + strings.ReplaceAll(version, `"`, "") + `"`
+}
+
+// IfMatch checks the If-Match header of an update against the current entity tag of the resource
+// Requests without the header are allowed through so clients can opt into optimistic concurrency
+func IfMatch(r *http.Request, etag string) error {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return nil
+	}
+
+	if !matchETag(header, etag, false) {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// noneMatch checks if the If-None-Match header matches the entity tag so a read can respond not modified
+func noneMatch(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	return matchETag(header, etag, true)
+}
+
+// matchETag checks if any tag in a conditional header matches the entity tag
+// Weak comparison ignores the W/ prefix while strong comparison never matches weak tags
+func matchETag(header string, etag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
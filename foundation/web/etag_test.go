@@ -0,0 +1,95 @@
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_ETag(t *testing.T) {
+	app := web.NewApp(nil)
+	app.Handle("GET /reviews/{id}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, map[string]string{"id": web.Param(r, "id")}, http.StatusOK)
+	})
+	app.Handle("GET /versioned", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("ETag", web.VersionETag("7"))
+		return web.Respond(ctx, w, map[string]string{"id": "versioned"}, http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/reviews/1", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("Should set an ETag on successful reads")
+	}
+
+	cases := []struct {
+		name           string
+		path           string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedETag   string
+	}{
+		{name: "Success_NotModified", path: "/reviews/1", ifNoneMatch: etag, expectedStatus: http.StatusNotModified, expectedETag: etag},
+		{name: "Success_WeakNotModified", path: "/reviews/1", ifNoneMatch: `"other", W/` + etag, expectedStatus: http.StatusNotModified, expectedETag: etag},
+		{name: "Success_Modified", path: "/reviews/2", ifNoneMatch: etag, expectedStatus: http.StatusOK},
+		{name: "Success_Version", path: "/versioned", ifNoneMatch: `"7"`, expectedStatus: http.StatusNotModified, expectedETag: `"7"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+			r.Header.Set("If-None-Match", c.ifNoneMatch)
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != c.expectedStatus {
+				t.Fatalf("Should respond with %d, got %d", c.expectedStatus, w.Code)
+			}
+
+			if c.expectedETag != "" && w.Header().Get("ETag") != c.expectedETag {
+				t.Errorf("Should respond with ETag %s, got %s", c.expectedETag, w.Header().Get("ETag"))
+			}
+
+			if c.expectedStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("Should not write a body when not modified, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func Test_IfMatch(t *testing.T) {
+	etag := web.VersionETag("3")
+
+	cases := []struct {
+		name     string
+		ifMatch  string
+		expected error
+	}{
+		{name: "Success_NoHeader", expected: nil},
+		{name: "Success_Match", ifMatch: `"3"`, expected: nil},
+		{name: "Success_Any", ifMatch: "*", expected: nil},
+		{name: "Fail_Stale", ifMatch: `"2"`, expected: web.ErrPreconditionFailed},
+		{name: "Fail_Weak", ifMatch: `W/"3"`, expected: web.ErrPreconditionFailed},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/reviews/1", nil)
+			if c.ifMatch != "" {
+				r.Header.Set("If-Match", c.ifMatch)
+			}
+
+			if err := web.IfMatch(r, etag); !errors.Is(err, c.expected) {
+				t.Errorf("Should return %v, got %v", c.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package web
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultMultipartMemory is how much of a multipart body is held in memory before files spill to disk
+const defaultMultipartMemory int64 = 32 << 20
+
+// FormDecoder decodes application/x-www-form-urlencoded request bodies into structs
+// Fields are matched by their form tag, falling back to their json tag and then their name
+type FormDecoder struct{}
+
+// MediaType returns the media type handled by the decoder
+func (FormDecoder) MediaType() string {
+	return "application/x-www-form-urlencoded"
+}
+
+// Decode parses the form and sets the fields of the struct v points to
+func (FormDecoder) Decode(r *http.Request, v any) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	return decodeForm(v, r.PostForm, nil)
+}
+
+// MultipartDecoder decodes multipart/form-data request bodies into structs
+// Fields of type *multipart.FileHeader or []*multipart.FileHeader receive uploaded files
+type MultipartDecoder struct {
+	MaxMemory int64
+}
+
+// MediaType returns the media type handled by the decoder
+func (MultipartDecoder) MediaType() string {
+	return "multipart/form-data"
+}
+
+// Decode parses the multipart form and sets the fields of the struct v points to
+func (d MultipartDecoder) Decode(r *http.Request, v any) error {
+	maxMemory := d.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = defaultMultipartMemory
+	}
+
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		return err
+	}
+
+	return decodeForm(v, r.MultipartForm.Value, r.MultipartForm.File)
+}
+
+// =============================================================================
+
+var (
+	fileHeaderType      = reflect.TypeFor[*multipart.FileHeader]()
+	fileHeadersType     = reflect.TypeFor[[]*multipart.FileHeader]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// decodeForm sets the fields of the struct v points to from form values and files
+func decodeForm(v any, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("form can only be decoded into a pointer to a struct")
+	}
+
+	return decodeStruct(rv.Elem(), values, files)
+}
+
+// decodeStruct sets each exported field of a struct, flattening embedded structs
+func decodeStruct(rv reflect.Value, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+	rt := rv.Type()
+
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(fv, values, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := fieldName(sf)
+		if name == "" {
+			continue
+		}
+
+		switch sf.Type {
+		case fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs[0]))
+			}
+			continue
+		case fileHeadersType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs))
+			}
+			continue
+		}
+
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+
+		if err := setField(fv, vals); err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the form key for a struct field or an empty string if the field is skipped
+func fieldName(sf reflect.StructField) string {
+	for _, key := range []string{"form", "json"} {
+		tag, ok := sf.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return sf.Name
+}
+
+// setField sets a field from its form values, using every value for slices and the first otherwise
+func setField(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Slice && !implementsText(fv.Type()) {
+		slice := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setValue(slice.Index(i), val); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setValue(fv, vals[0])
+}
+
+// setValue parses a single form value into a field
+func setValue(fv reflect.Value, val string) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setValue(fv.Elem(), val)
+	}
+
+	if implementsText(fv.Type()) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(val)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// implementsText checks if a pointer to the type can unmarshal itself from text
+func implementsText(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,71 @@
+package web
+
+import "strings"
+
+// Router represents anything routes can be registered on, such as the App or one of its groups
+type Router interface {
+	Handle(pattern string, handler Handler, middleware ...Middleware) *Route
+	HandleNoAppMiddleware(pattern string, handler Handler, middleware ...Middleware) *Route
+	Group(prefix string, middleware ...Middleware) *Group
+}
+
+// Group registers routes under a shared path prefix and middleware
+type Group struct {
+	app        *App
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a group of routes under a path prefix that share middleware
+func (a *App) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		app:        a,
+		prefix:     cleanPrefix(prefix),
+		middleware: middleware,
+	}
+}
+
+// Group creates a nested group that extends the prefix and runs its middleware after the parent's
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		app:        g.app,
+		prefix:     g.prefix + cleanPrefix(prefix),
+		middleware: g.stack(middleware),
+	}
+}
+
+// Handle sets a handler function for an HTTP method and path within the group and includes app middleware
+func (g *Group) Handle(pattern string, handler Handler, middleware ...Middleware) *Route {
+	return g.app.Handle(g.pattern(pattern), handler, g.stack(middleware)...)
+}
+
+// HandleNoAppMiddleware sets a handler function for an HTTP method and path within the group and excludes app middleware
+func (g *Group) HandleNoAppMiddleware(pattern string, handler Handler, middleware ...Middleware) *Route {
+	return g.app.HandleNoAppMiddleware(g.pattern(pattern), handler, g.stack(middleware)...)
+}
+
+// pattern prefixes the path of a pattern with the group prefix
+func (g *Group) pattern(pattern string) string {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return g.prefix + pattern
+	}
+
+	return method + " " + g.prefix + strings.TrimSpace(path)
+}
+
+// stack returns the group middleware followed by the given middleware
+func (g *Group) stack(middleware []Middleware) []Middleware {
+	mw := make([]Middleware, 0, len(g.middleware)+len(middleware))
+	mw = append(mw, g.middleware...)
+	return append(mw, middleware...)
+}
+
+// cleanPrefix normalizes a prefix to start with a slash and not end with one
+func cleanPrefix(prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
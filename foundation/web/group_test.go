@@ -0,0 +1,65 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_Group(t *testing.T) {
+	var order []string
+	trace := func(name string) web.Middleware {
+		return func(handler web.Handler) web.Handler {
+			return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+				order = append(order, name)
+				return handler(ctx, w, r)
+			}
+		}
+	}
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		order = append(order, "handler")
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	}
+
+	app := web.NewApp(nil, trace("app"))
+
+	v1 := app.Group("/v1", trace("v1"))
+	v1.Handle("GET /reviews", handler)
+
+	admin := v1.Group("admin/", trace("admin"))
+	admin.Handle("DELETE /reviews/{id}", handler, trace("route"))
+	admin.HandleNoAppMiddleware("GET /health", handler)
+
+	cases := []struct {
+		name          string
+		method        string
+		path          string
+		expectedOrder string
+	}{
+		{name: "Success_Group", method: http.MethodGet, path: "/v1/reviews", expectedOrder: "app,v1,handler"},
+		{name: "Success_NestedGroup", method: http.MethodDelete, path: "/v1/admin/reviews/1", expectedOrder: "app,v1,admin,route,handler"},
+		{name: "Success_NoAppMiddleware", method: http.MethodGet, path: "/v1/admin/health", expectedOrder: "v1,admin,handler"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			order = nil
+
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(c.method, c.path, nil))
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("Should route %s %s, got %d", c.method, c.path, w.Code)
+			}
+
+			if got := strings.Join(order, ","); got != c.expectedOrder {
+				t.Errorf("Should run middleware in order %s, got %s", c.expectedOrder, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package web
+
+import "sync"
+
+// Hub fans messages out to websocket clients grouped into rooms
+type Hub struct {
+	mu    sync.RWMutex
+	rooms map[string]map[*WebSocket]struct{}
+}
+
+// NewHub constructs a new Hub
+func NewHub() *Hub {
+	return &Hub{
+		rooms: make(map[string]map[*WebSocket]struct{}),
+	}
+}
+
+// Join adds a client to a room
+func (h *Hub) Join(room string, ws *WebSocket) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients, ok := h.rooms[room]
+	if !ok {
+		clients = make(map[*WebSocket]struct{})
+		h.rooms[room] = clients
+	}
+	clients[ws] = struct{}{}
+}
+
+// Leave removes a client from a room and deletes the room once it is empty
+func (h *Hub) Leave(room string, ws *WebSocket) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients, ok := h.rooms[room]
+	if !ok {
+		return
+	}
+
+	delete(clients, ws)
+	if len(clients) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+// Broadcast queues a message for every client in a room and returns how many received it
+// Clients that cannot keep up are disconnected rather than slowing down the room
+func (h *Hub) Broadcast(room string, msg []byte) int {
+	h.mu.RLock()
+	clients := make([]*WebSocket, 0, len(h.rooms[room]))
+	for ws := range h.rooms[room] {
+		clients = append(clients, ws)
+	}
+	h.mu.RUnlock()
+
+	var sent int
+	for _, ws := range clients {
+		if ws.Send(msg) {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+// Count returns the number of clients in a room
+func (h *Hub) Count(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.rooms[room])
+}
@@ -1,9 +1,23 @@
 package web
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"strings"
+)
+
+// DefaultBodyLimit is the maximum size of a request body when the app does not set one
+const DefaultBodyLimit int64 = 1 << 20
+
+// Errors returned when a request body cannot be decoded
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrBodyTooLarge         = errors.New("request body too large")
+	ErrInvalidBody          = errors.New("invalid request body")
 )
 
 // Param returns the parameters from the request path
@@ -12,24 +26,46 @@ func Param(r *http.Request, key string) string {
 }
 
 // Decoder represents data that can be decoded
+// Values passed to Decode that implement it decode their own payload once the content type is accepted
 type Decoder interface {
 	Decode(data []byte) error
 }
 
+// BodyDecoder represents a request body format that can be decoded into a value
+type BodyDecoder interface {
+	MediaType() string
+	Decode(r *http.Request, v any) error
+}
+
 // Validator represents data that can be validated
 type validator interface {
 	Validate() error
 }
 
 // Decode reads from the HTTP request, decodes the data into a structure, and optionally validates the data
-func Decode(r *http.Request, v Decoder) error {
-	data, err := io.ReadAll(r.Body)
+// The Content-Type of the request selects the decoder and defaults to accepting JSON only
+func Decode(r *http.Request, v any, decoders ...BodyDecoder) error {
+	if len(decoders) == 0 {
+		decoders = []BodyDecoder{JSONDecoder{}}
+	}
+
+	d, err := selectDecoder(r, decoders)
 	if err != nil {
-		return fmt.Errorf("cannot read request payload: %w", err)
+		return err
 	}
 
-	if err := v.Decode(data); err != nil {
-		return fmt.Errorf("cannot decode request payload: %w", err)
+	if dv, ok := v.(Decoder); ok {
+		err = decodePayload(r, dv)
+	} else {
+		err = d.Decode(r, v)
+	}
+
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, mbe.Limit)
+		}
+		return fmt.Errorf("%w: %w", ErrInvalidBody, err)
 	}
 
 	if v, ok := v.(validator); ok {
@@ -40,3 +76,95 @@ func Decode(r *http.Request, v Decoder) error {
 
 	return nil
 }
+
+// decodePayload reads the whole body and passes it to a value that decodes itself
+func decodePayload(r *http.Request, v Decoder) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	return v.Decode(data)
+}
+
+// selectDecoder finds the decoder for the media type of the request
+func selectDecoder(r *http.Request, decoders []BodyDecoder) (BodyDecoder, error) {
+	supported := make([]string, len(decoders))
+	for i, d := range decoders {
+		supported[i] = d.MediaType()
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: expected %s", ErrUnsupportedMediaType, strings.Join(supported, ", "))
+	}
+
+	for _, d := range decoders {
+		if matchMediaType(mediaType, d.MediaType()) {
+			return d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q, expected %s", ErrUnsupportedMediaType, mediaType, strings.Join(supported, ", "))
+}
+
+// matchMediaType checks if a media type is the target or uses it as a structured syntax suffix
+// application/vnd.critiquefy.v1+json matches application/json
+func matchMediaType(mediaType string, target string) bool {
+	if mediaType == target {
+		return true
+	}
+
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	targetType, targetSubtype, _ := strings.Cut(target, "/")
+	_, suffix, found := strings.Cut(subtype, "+")
+
+	return found && typ == targetType && suffix == targetSubtype
+}
+
+// limitBody caps how much of the request body can be read
+// A negative limit leaves the body unlimited
+func limitBody(w http.ResponseWriter, r *http.Request, limit int64) {
+	if limit < 0 || r.Body == nil {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+}
+
+// =============================================================================
+
+// JSONDecoder decodes application/json request bodies
+type JSONDecoder struct {
+	Strict bool
+}
+
+// MediaType returns the media type handled by the decoder
+func (JSONDecoder) MediaType() string {
+	return "application/json"
+}
+
+// Decode decodes a single JSON value and rejects unknown fields in strict mode
+func (d JSONDecoder) Decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	if d.Strict {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("body is empty")
+		}
+		return err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return err
+		}
+		return errors.New("body must contain a single JSON value")
+	}
+
+	return nil
+}
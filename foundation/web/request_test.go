@@ -0,0 +1,136 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+type newReview struct {
+	Title  string   `json:"title"`
+	Score  int      `json:"score"`
+	Tags   []string `json:"tags" form:"tag"`
+	Hidden bool     `json:"-"`
+}
+
+func Test_Decode(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		limit       int64
+		decoders    []web.BodyDecoder
+		expected    newReview
+		expectedErr error
+	}{
+		{name: "Success_JSON", contentType: "application/json", body: `{"title":"Dune","score":5}`, expected: newReview{Title: "Dune", Score: 5}},
+		{name: "Success_JSONCharset", contentType: "application/json; charset=utf-8", body: `{"title":"Dune"}`, expected: newReview{Title: "Dune"}},
+		{name: "Success_JSONSuffix", contentType: "application/vnd.critiquefy.v1+json", body: `{"title":"Dune"}`, expected: newReview{Title: "Dune"}},
+		{name: "Success_UnknownField", contentType: "application/json", body: `{"title":"Dune","extra":true}`, expected: newReview{Title: "Dune"}},
+		{name: "Success_Form", contentType: "application/x-www-form-urlencoded", body: "title=Dune&score=4&tag=scifi&tag=classic&Hidden=true", decoders: []web.BodyDecoder{web.FormDecoder{}}, expected: newReview{Title: "Dune", Score: 4, Tags: []string{"scifi", "classic"}}},
+		{name: "Fail_Strict", contentType: "application/json", body: `{"title":"Dune","extra":true}`, decoders: []web.BodyDecoder{web.JSONDecoder{Strict: true}}, expectedErr: web.ErrInvalidBody},
+		{name: "Fail_TrailingData", contentType: "application/json", body: `{"title":"Dune"}{"title":"Emma"}`, expectedErr: web.ErrInvalidBody},
+		{name: "Fail_Empty", contentType: "application/json", expectedErr: web.ErrInvalidBody},
+		{name: "Fail_NoContentType", body: `{"title":"Dune"}`, expectedErr: web.ErrUnsupportedMediaType},
+		{name: "Fail_ContentType", contentType: "text/plain", body: `{"title":"Dune"}`, expectedErr: web.ErrUnsupportedMediaType},
+		{name: "Fail_FormNotAccepted", contentType: "application/x-www-form-urlencoded", body: "title=Dune", expectedErr: web.ErrUnsupportedMediaType},
+		{name: "Fail_FormType", contentType: "application/x-www-form-urlencoded", body: "score=five", decoders: []web.BodyDecoder{web.FormDecoder{}}, expectedErr: web.ErrInvalidBody},
+		{name: "Fail_TooLarge", contentType: "application/json", body: `{"title":"` + strings.Repeat("a", 64) + `"}`, limit: 32, expectedErr: web.ErrBodyTooLarge},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got newReview
+			var err error
+
+			app := web.NewApp(nil)
+			route := app.Handle("POST /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+				err = web.Decode(r, &got, c.decoders...)
+				return nil
+			})
+			if c.limit != 0 {
+				route.BodyLimit(c.limit)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(c.body))
+			if c.contentType != "" {
+				r.Header.Set("Content-Type", c.contentType)
+			}
+
+			app.ServeHTTP(httptest.NewRecorder(), r)
+
+			if !errors.Is(err, c.expectedErr) {
+				t.Fatalf("Should return %v, got %v", c.expectedErr, err)
+			}
+
+			if c.expectedErr != nil {
+				return
+			}
+
+			if got.Title != c.expected.Title || got.Score != c.expected.Score || strings.Join(got.Tags, ",") != strings.Join(c.expected.Tags, ",") || got.Hidden {
+				t.Errorf("Should decode %+v, got %+v", c.expected, got)
+			}
+		})
+	}
+}
+
+// payload decodes its own body the way request types did before body decoders existed
+type payload struct {
+	data string
+}
+
+func (p *payload) Decode(data []byte) error {
+	p.data = string(data)
+	return nil
+}
+
+func Test_DecodePayload(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(`{"title":"Dune"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var got payload
+	if err := web.Decode(r, &got); err != nil {
+		t.Fatalf("Should be able to decode the payload: %s", err)
+	}
+
+	if got.data != `{"title":"Dune"}` {
+		t.Errorf("Should pass the whole body to the payload's own Decode, got %q", got.data)
+	}
+}
+
+func Test_DecodeMultipart(t *testing.T) {
+	type upload struct {
+		Caption string                `form:"caption"`
+		Cover   *multipart.FileHeader `form:"cover"`
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("caption", "First edition")
+	fw, _ := mw.CreateFormFile("cover", "cover.png")
+	fw.Write([]byte("png"))
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/reviews/1/cover", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var got upload
+	if err := web.Decode(r, &got, web.MultipartDecoder{}); err != nil {
+		t.Fatalf("Should be able to decode multipart body: %s", err)
+	}
+
+	if got.Caption != "First edition" {
+		t.Errorf("Should decode the caption, got %q", got.Caption)
+	}
+
+	if got.Cover == nil || got.Cover.Filename != "cover.png" || got.Cover.Size != 3 {
+		t.Errorf("Should decode the cover file, got %+v", got.Cover)
+	}
+}
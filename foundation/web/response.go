@@ -3,24 +3,44 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 )
 
 // Respond sets the HTTP response data
+// Successful reads are tagged with an ETag, using one already set by the handler or a hash of the body,
+// and respond not modified when it matches the If-None-Match header
 func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode int) error {
-	setStatusCode(ctx, statusCode)
-
 	if statusCode == http.StatusNoContent {
+		setStatusCode(ctx, statusCode)
 		w.WriteHeader(statusCode)
 		return nil
 	}
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
+		setStatusCode(ctx, statusCode)
 		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+
+	if v, ok := ctx.Value(key).(*Values); ok && statusCode == http.StatusOK && safeMethod(v.method) {
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			etag = ETag(jsonData)
+			w.Header().Set("ETag", etag)
+		}
+
+		if noneMatch(v.header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			setStatusCode(ctx, http.StatusNotModified)
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	setStatusCode(ctx, statusCode)
 	w.WriteHeader(statusCode)
 
 	if _, err := w.Write(jsonData); err != nil {
@@ -29,3 +49,81 @@ func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode in
 
 	return nil
 }
+
+// RespondBytes writes an already encoded body using the headers already set on the response
+func RespondBytes(ctx context.Context, w http.ResponseWriter, data []byte, statusCode int) error {
+	setStatusCode(ctx, statusCode)
+
+	w.WriteHeader(statusCode)
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// safeMethod checks if an HTTP method only reads data
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// ArrayWriter streams a JSON array to the response one element at a time
+// Once the first byte is written the status code can no longer change, so errors
+// returned mid-stream only terminate the response
+type ArrayWriter struct {
+	w     http.ResponseWriter
+	count int
+}
+
+// RespondArray writes the response header and opens a JSON array that elements can be streamed into
+func RespondArray(ctx context.Context, w http.ResponseWriter, statusCode int) (*ArrayWriter, error) {
+	setStatusCode(ctx, statusCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, err
+	}
+
+	return &ArrayWriter{w: w}, nil
+}
+
+// Write encodes an element and appends it to the array
+func (aw *ArrayWriter) Write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if aw.count > 0 {
+		if _, err := io.WriteString(aw.w, ","); err != nil {
+			return err
+		}
+	}
+
+	if _, err := aw.w.Write(data); err != nil {
+		return err
+	}
+	aw.count++
+
+	return nil
+}
+
+// Flush sends the elements written so far to the client
+func (aw *ArrayWriter) Flush() error {
+	return http.NewResponseController(aw.w).Flush()
+}
+
+// Close terminates the array
+func (aw *ArrayWriter) Close() error {
+	if _, err := io.WriteString(aw.w, "]"); err != nil {
+		return err
+	}
+	return nil
+}
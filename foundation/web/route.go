@@ -0,0 +1,91 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Route represents a registered pattern and its documentation
+type Route struct {
+	Pattern   string
+	Doc       RouteDoc
+	bodyLimit int64
+}
+
+// RouteDoc describes a route for API documentation
+// Request and Response hold zero values of the types that are decoded and responded with
+type RouteDoc struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Request     any
+	Response    any
+	Status      int
+	Auth        bool
+	Roles       []string
+	Errors      []ErrorDoc
+	Deprecated  bool
+}
+
+// ErrorDoc describes an error a route can respond with
+type ErrorDoc struct {
+	Status int
+	Code   string
+}
+
+// Describe attaches documentation to the route
+func (r *Route) Describe(doc RouteDoc) *Route {
+	r.Doc = doc
+	return r
+}
+
+// BodyLimit overrides the maximum request body size of the app for the route
+// A negative limit leaves request bodies unlimited
+func (r *Route) BodyLimit(limit int64) *Route {
+	r.bodyLimit = limit
+	return r
+}
+
+// limit returns the body limit of the route or the app limit if the route does not set one
+func (r *Route) limit(appLimit int64) int64 {
+	if r.bodyLimit == 0 {
+		return appLimit
+	}
+	return r.bodyLimit
+}
+
+// Method returns the HTTP method of the route pattern
+func (r *Route) Method() string {
+	method, _, found := strings.Cut(r.Pattern, " ")
+	if !found {
+		return ""
+	}
+	return method
+}
+
+// Path returns the path of the route pattern
+func (r *Route) Path() string {
+	_, path, found := strings.Cut(r.Pattern, " ")
+	if !found {
+		return r.Pattern
+	}
+	return strings.TrimSpace(path)
+}
+
+// Routes returns the routes registered on the app sorted by pattern, excluding generated preflight routes
+func (a *App) Routes() []Route {
+	routes := make([]Route, 0, len(a.routes))
+	for _, r := range a.routes {
+		if r.Method() == http.MethodOptions && r.Doc.Summary == "" {
+			continue
+		}
+		routes = append(routes, *r)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern < routes[j].Pattern
+	})
+
+	return routes
+}
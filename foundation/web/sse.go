@@ -0,0 +1,175 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeat defines how often an idle event stream sends a comment to keep proxies from closing it
+const DefaultHeartbeat = 15 * time.Second
+
+// Event represents a single server-sent event
+// Data is written as is when it is a string or byte slice and encoded as JSON otherwise
+type Event struct {
+	ID    string
+	Name  string
+	Data  any
+	Retry time.Duration
+}
+
+// EventStream writes server-sent events to a client
+type EventStream struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	mu          sync.Mutex
+	lastEventID string
+	closing     <-chan struct{}
+}
+
+// NewEventStream starts an event stream on the response
+// The write deadline configured on the server is cleared since streams are expected to outlive it
+func NewEventStream(ctx context.Context, w http.ResponseWriter, r *http.Request) (*EventStream, error) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, fmt.Errorf("clearing write deadline: %w", err)
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+
+	setStatusCode(ctx, http.StatusOK)
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return nil, fmt.Errorf("flushing stream: %w", err)
+	}
+
+	es := EventStream{
+		w:           w,
+		rc:          rc,
+		lastEventID: lastEventID,
+		closing:     Closing(ctx),
+	}
+
+	return &es, nil
+}
+
+// LastEventID returns the ID of the last event the client received before reconnecting
+// Handlers use it to resend the events that were missed
+func (es *EventStream) LastEventID() string {
+	return es.lastEventID
+}
+
+// Send writes an event to the client and flushes it
+func (es *EventStream) Send(ev Event) error {
+	var buf bytes.Buffer
+
+	if ev.ID != "" {
+		fmt.Fprintf(&buf, "id: %s\n", sanitizeField(ev.ID))
+	}
+
+	if ev.Name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", sanitizeField(ev.Name))
+	}
+
+	if ev.Retry > 0 {
+		fmt.Fprintf(&buf, "retry: %s\n", strconv.FormatInt(ev.Retry.Milliseconds(), 10))
+	}
+
+	var data []byte
+	switch v := ev.Data.(type) {
+	case nil:
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		jsonData, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encoding event data: %w", err)
+		}
+		data = jsonData
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(bytes.TrimSuffix(line, []byte("\r")))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	return es.write(buf.Bytes())
+}
+
+// Comment writes a comment the client ignores, which keeps idle connections open
+func (es *EventStream) Comment(text string) error {
+	return es.write([]byte(": " + sanitizeField(text) + "\n\n"))
+}
+
+// Run sends events from the channel with heartbeats in between until the channel closes,
+// the client disconnects, or the server shuts down
+func (es *EventStream) Run(ctx context.Context, events <-chan Event, heartbeat time.Duration) error {
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-es.closing:
+			return nil
+
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := es.Send(ev); err != nil {
+				return err
+			}
+			ticker.Reset(heartbeat)
+
+		case <-ticker.C:
+			if err := es.Comment("heartbeat"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// write sends bytes to the client and flushes them immediately
+func (es *EventStream) write(data []byte) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if _, err := es.w.Write(data); err != nil {
+		return err
+	}
+
+	return es.rc.Flush()
+}
+
+// sanitizeField strips line breaks that would terminate a field early
+func sanitizeField(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,92 @@
+package web_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_EventStream(t *testing.T) {
+	app := web.NewApp(nil)
+	app.Handle("GET /events", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		es, err := web.NewEventStream(ctx, w, r)
+		if err != nil {
+			return err
+		}
+
+		events := make(chan web.Event, 2)
+		events <- web.Event{ID: es.LastEventID() + "1", Name: "review", Data: map[string]string{"id": "1"}, Retry: time.Second}
+		events <- web.Event{ID: es.LastEventID() + "2", Data: "line one\nline two"}
+
+		return es.Run(ctx, events, 10*time.Millisecond)
+	})
+
+	srv := httptest.NewUnstartedServer(app)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Config.RegisterOnShutdown(app.CloseStreams)
+	srv.Start()
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/events", nil)
+	if err != nil {
+		t.Fatalf("Should be able to create a request: %s", err)
+	}
+	req.Header.Set("Last-Event-ID", "4")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Should be able to connect to the stream: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Should respond with an event stream, got %q", ct)
+	}
+
+	expected := []string{
+		"id: 41", "event: review", "retry: 1000", `data: {"id":"1"}`, "",
+		"id: 42", "data: line one", "data: line two", "",
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for _, line := range expected {
+		if !scanner.Scan() {
+			t.Fatalf("Should receive %q: %v", line, scanner.Err())
+		}
+		if scanner.Text() != line {
+			t.Fatalf("Should receive %q, got %q", line, scanner.Text())
+		}
+	}
+
+	// Outlive the write timeout to prove the stream cleared it
+	time.Sleep(100 * time.Millisecond)
+
+	var heartbeat bool
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), ": heartbeat") {
+			heartbeat = true
+			break
+		}
+	}
+	if !heartbeat {
+		t.Fatalf("Should receive heartbeats after the write timeout: %v", scanner.Err())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		srv.Config.Shutdown(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Should end the stream when the server shuts down")
+	}
+}
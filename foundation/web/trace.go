@@ -0,0 +1,228 @@
+package web
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
+)
+
+// Trace context headers defined by the W3C Trace Context specification
+const (
+	headerTraceparent = "traceparent"
+	headerTracestate  = "tracestate"
+	headerTraceID     = "X-Trace-Id"
+)
+
+// maxTracestateLen is the longest tracestate value that is propagated
+const maxTracestateLen = 512
+
+// flagSampled marks a trace as sampled by the caller
+const flagSampled byte = 0x01
+
+// zeroTraceID is reported when the context does not carry a trace
+const zeroTraceID = "00000000000000000000000000000000"
+
+// traceContext represents the position of a request within a distributed trace
+type traceContext struct {
+	traceID      string
+	spanID       string
+	parentSpanID string
+	flags        byte
+	state        string
+}
+
+// newTraceContext continues the trace of the incoming request or starts a new one
+// Every request gets its own span id with the caller's span as its parent
+// New traces are sampled when the tracer is missing or decides to record them
+func newTraceContext(r *http.Request, t *tracer.Tracer) traceContext {
+	tc, ok := parseTraceparent(r.Header.Get(headerTraceparent))
+	if !ok {
+		tc = traceContext{
+			traceID: tracer.NewTraceID(),
+			spanID:  tracer.NewSpanID(),
+		}
+
+		if t == nil || t.Sample(tc.traceID) {
+			tc.flags = flagSampled
+		}
+
+		return tc
+	}
+
+	tc.parentSpanID = tc.spanID
+	tc.spanID = tracer.NewSpanID()
+
+	if state := strings.TrimSpace(r.Header.Get(headerTracestate)); len(state) <= maxTracestateLen {
+		tc.state = state
+	}
+
+	return tc
+}
+
+// parseTraceparent parses a traceparent header in the form version-traceid-spanid-flags
+func parseTraceparent(header string) (traceContext, bool) {
+	header = strings.TrimSpace(header)
+	if len(header) < 55 {
+		return traceContext{}, false
+	}
+
+	version := header[:2]
+	if !isLowerHex(version) || version == "ff" {
+		return traceContext{}, false
+	}
+
+	// Version 00 has an exact length while later versions may append fields
+	switch {
+	case version == "00" && len(header) != 55:
+		return traceContext{}, false
+	case len(header) > 55 && header[55] != '-':
+		return traceContext{}, false
+	}
+
+	if header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return traceContext{}, false
+	}
+
+	traceID := header[3:35]
+	spanID := header[36:52]
+	flags := header[53:55]
+
+	if !isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) {
+		return traceContext{}, false
+	}
+
+	if traceID == zeroTraceID || spanID == "0000000000000000" {
+		return traceContext{}, false
+	}
+
+	b, _ := hex.DecodeString(flags)
+
+	tc := traceContext{
+		traceID: traceID,
+		spanID:  spanID,
+		flags:   b[0],
+	}
+
+	return tc, true
+}
+
+// traceparent formats the trace context as a traceparent header value
+func (tc traceContext) traceparent() string {
+	return "00-" + tc.traceID + "-" + tc.spanID + "-" + hex.EncodeToString([]byte{tc.flags})
+}
+
+// isLowerHex checks if a string only contains lowercase hexadecimal characters
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// =============================================================================
+
+// spanContext returns the ids of the request span for the tracer
+func (tc traceContext) spanContext() tracer.SpanContext {
+	sc := tracer.SpanContext{
+		TraceID:      tc.traceID,
+		SpanID:       tc.spanID,
+		ParentSpanID: tc.parentSpanID,
+		Sampled:      tc.flags&flagSampled != 0,
+	}
+
+	return sc
+}
+
+// GetSpanID retrieves the id of the span handling the request from the context
+func GetSpanID(ctx context.Context) string {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return ""
+	}
+	return v.SpanID
+}
+
+// Sampled reports if the caller asked for the trace to be recorded
+func Sampled(ctx context.Context) bool {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return false
+	}
+	return v.trace.flags&flagSampled != 0
+}
+
+// InjectTrace writes the trace context of ctx into the headers of an outbound request
+// The active span, or the span of the request if nothing is recorded, becomes the parent of the downstream span
+func InjectTrace(ctx context.Context, header http.Header) {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return
+	}
+
+	tc := v.trace
+	if span := tracer.FromContext(ctx); span != nil {
+		tc.spanID = span.SpanID()
+	}
+
+	header.Set(headerTraceparent, tc.traceparent())
+	if v.trace.state != "" {
+		header.Set(headerTracestate, v.trace.state)
+	}
+}
+
+// Transport is an http.RoundTripper that propagates the trace context of outbound requests
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip records a client span, adds the trace context headers and sends the request with the base transport
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if _, ok := r.Context().Value(key).(*Values); !ok {
+		return base.RoundTrip(r)
+	}
+
+	ctx, span := tracer.StartClient(r.Context(), "HTTP "+r.Method)
+	defer span.End()
+
+	span.SetAttr("http.request.method", r.Method)
+	span.SetAttr("server.address", r.URL.Host)
+	span.SetAttr("url.full", r.URL.Redacted())
+
+	r = r.Clone(ctx)
+	InjectTrace(ctx, r.Header)
+
+	resp, err := base.RoundTrip(r)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+
+	span.SetAttr("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(tracer.StatusError, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// NewClient returns an HTTP client that propagates the trace context of each request
+func NewClient(base *http.Client) *http.Client {
+	c := http.Client{}
+	if base != nil {
+		c = *base
+	}
+
+	c.Transport = &Transport{Base: c.Transport}
+
+	return &c
+}
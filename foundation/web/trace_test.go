@@ -0,0 +1,90 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+)
+
+func Test_TraceContext(t *testing.T) {
+	const upstreamTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const upstreamSpan = "00f067aa0ba902b7"
+
+	cases := []struct {
+		name          string
+		traceparent   string
+		tracestate    string
+		expectedTrace string
+		expectedState string
+	}{
+		{name: "Success_Upstream", traceparent: "00-" + upstreamTrace + "-" + upstreamSpan + "-01", tracestate: "congo=t61rcWkgMzE", expectedTrace: upstreamTrace, expectedState: "congo=t61rcWkgMzE"},
+		{name: "Success_FutureVersion", traceparent: "01-" + upstreamTrace + "-" + upstreamSpan + "-01-extra", expectedTrace: upstreamTrace},
+		{name: "Success_NoHeader"},
+		{name: "Fail_Uppercase", traceparent: "00-" + strings.ToUpper(upstreamTrace) + "-" + upstreamSpan + "-01"},
+		{name: "Fail_ZeroTrace", traceparent: "00-00000000000000000000000000000000-" + upstreamSpan + "-01"},
+		{name: "Fail_InvalidVersion", traceparent: "ff-" + upstreamTrace + "-" + upstreamSpan + "-01"},
+		{name: "Fail_Version00Extra", traceparent: "00-" + upstreamTrace + "-" + upstreamSpan + "-01-extra"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var outbound http.Header
+			var traceID, spanID string
+
+			app := web.NewApp(nil)
+			app.Handle("GET /reviews", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+				traceID = web.GetTraceID(ctx)
+				spanID = web.GetSpanID(ctx)
+
+				outbound = http.Header{}
+				web.InjectTrace(ctx, outbound)
+
+				return nil
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/reviews", nil)
+			if c.traceparent != "" {
+				r.Header.Set("traceparent", c.traceparent)
+			}
+			if c.tracestate != "" {
+				r.Header.Set("tracestate", c.tracestate)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if len(traceID) != 32 || traceID == "00000000000000000000000000000000" {
+				t.Fatalf("Should assign a valid trace id, got %q", traceID)
+			}
+
+			if c.expectedTrace != "" && traceID != c.expectedTrace {
+				t.Errorf("Should reuse the upstream trace id %s, got %s", c.expectedTrace, traceID)
+			}
+
+			if c.expectedTrace == "" && traceID == upstreamTrace {
+				t.Errorf("Should not reuse an invalid upstream trace id")
+			}
+
+			if len(spanID) != 16 || spanID == upstreamSpan {
+				t.Errorf("Should generate a new span id, got %q", spanID)
+			}
+
+			if got := w.Header().Get("X-Trace-Id"); got != traceID {
+				t.Errorf("Should echo the trace id, got %q", got)
+			}
+
+			expected := "00-" + traceID + "-" + spanID + "-01"
+			if got := outbound.Get("traceparent"); got != expected {
+				t.Errorf("Should propagate traceparent %s, got %s", expected, got)
+			}
+
+			if got := outbound.Get("tracestate"); got != c.expectedState {
+				t.Errorf("Should propagate tracestate %q, got %q", c.expectedState, got)
+			}
+		})
+	}
+}
@@ -3,12 +3,15 @@ package web
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/tracer"
 )
 
 // Handler represents logic that can handle an HTTP request
@@ -19,14 +22,28 @@ type App struct {
 	*http.ServeMux
 	shutdown      chan os.Signal
 	appMiddleware []Middleware
+	cors          bool
+	patterns      map[string]struct{}
+	closing       chan struct{}
+	closeOnce     sync.Once
+	rewrites      []RewriteFn
+	routes        []*Route
+	bodyLimit     int64
+	tracer        *tracer.Tracer
 }
 
+// RewriteFn represents a function that can change a request before it is routed
+type RewriteFn func(r *http.Request) *http.Request
+
 // NewApp creates a new web application
 func NewApp(shutdown chan os.Signal, appMiddleware ...Middleware) *App {
 	return &App{
 		ServeMux:      http.NewServeMux(),
 		shutdown:      shutdown,
 		appMiddleware: appMiddleware,
+		patterns:      make(map[string]struct{}),
+		closing:       make(chan struct{}),
+		bodyLimit:     DefaultBodyLimit,
 	}
 }
 
@@ -35,17 +52,55 @@ func (a *App) SignalShutdown() {
 	a.shutdown <- syscall.SIGTERM
 }
 
+// Rewrite registers a function that can change a request before it is routed
+func (a *App) Rewrite(fn RewriteFn) {
+	a.rewrites = append(a.rewrites, fn)
+}
+
+// ServeHTTP applies the registered rewrites and routes the request
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, fn := range a.rewrites {
+		r = fn(r)
+	}
+
+	a.ServeMux.ServeHTTP(w, r)
+}
+
+// SetBodyLimit sets the maximum request body size for routes that do not set their own
+// A negative limit leaves request bodies unlimited
+func (a *App) SetBodyLimit(limit int64) {
+	a.bodyLimit = limit
+}
+
+// SetTracer records a span for every request the tracer samples
+func (a *App) SetTracer(t *tracer.Tracer) {
+	a.tracer = t
+}
+
+// CloseStreams signals long-lived handlers to finish so the server can shut down
+// Register it with http.Server.RegisterOnShutdown since Shutdown does not cancel active requests
+func (a *App) CloseStreams() {
+	a.closeOnce.Do(func() {
+		close(a.closing)
+	})
+}
+
 // Handle sets a handler function for an HTTP method and path and includes app middleware
-func (a *App) Handle(pattern string, handler Handler, middleware ...Middleware) {
+func (a *App) Handle(pattern string, handler Handler, middleware ...Middleware) *Route {
 	handler = wrapMiddleware(middleware, handler)
 	handler = wrapMiddleware(a.appMiddleware, handler)
 
+	var route *Route
+
 	h := func(w http.ResponseWriter, r *http.Request) {
-		v := Values{
-			TraceID: uuid.NewString(),
-			Now:     time.Now().UTC(),
-		}
-		ctx := setValues(r.Context(), &v)
+		limitBody(w, r, route.limit(a.bodyLimit))
+
+		v := a.newValues(r)
+		w.Header().Set(headerTraceID, v.TraceID)
+		ctx := setValues(r.Context(), v)
+
+		ctx, span := a.startSpan(ctx, pattern, r)
+		defer a.endSpan(span, v)
 
 		if err := handler(ctx, w, r); err != nil {
 			if validateError(err) {
@@ -55,19 +110,26 @@ func (a *App) Handle(pattern string, handler Handler, middleware ...Middleware)
 		}
 	}
 
-	a.HandleFunc(pattern, h)
+	route = a.register(pattern, h)
+
+	return route
 }
 
 // HandleNoAppMiddleware sets a handler function for an HTTP method and path and excludes app middleware
-func (a *App) HandleNoAppMiddleware(pattern string, handler Handler, middleware ...Middleware) {
+func (a *App) HandleNoAppMiddleware(pattern string, handler Handler, middleware ...Middleware) *Route {
 	handler = wrapMiddleware(middleware, handler)
 
+	var route *Route
+
 	h := func(w http.ResponseWriter, r *http.Request) {
-		v := Values{
-			TraceID: uuid.NewString(),
-			Now:     time.Now().UTC(),
-		}
-		ctx := setValues(r.Context(), &v)
+		limitBody(w, r, route.limit(a.bodyLimit))
+
+		v := a.newValues(r)
+		w.Header().Set(headerTraceID, v.TraceID)
+		ctx := setValues(r.Context(), v)
+
+		ctx, span := a.startSpan(ctx, pattern, r)
+		defer a.endSpan(span, v)
 
 		if err := handler(ctx, w, r); err != nil {
 			if validateError(err) {
@@ -77,7 +139,145 @@ func (a *App) HandleNoAppMiddleware(pattern string, handler Handler, middleware
 		}
 	}
 
+	route = a.register(pattern, h)
+
+	return route
+}
+
+// newValues creates the request values, continuing the trace of the caller if it sent one
+// The request header is kept rather than copied so middleware can normalize conditional headers before responding
+func (a *App) newValues(r *http.Request) *Values {
+	tc := newTraceContext(r, a.tracer)
+
+	v := Values{
+		TraceID: tc.traceID,
+		SpanID:  tc.spanID,
+		Now:     time.Now().UTC(),
+		trace:   tc,
+		method:  r.Method,
+		header:  r.Header,
+		closing: a.closing,
+	}
+
+	return &v
+}
+
+// startSpan starts the server span of a request named after the pattern it matched
+func (a *App) startSpan(ctx context.Context, pattern string, r *http.Request) (context.Context, *tracer.Span) {
+	v := GetValues(ctx)
+
+	ctx, span := a.tracer.StartServer(ctx, pattern, v.trace.spanContext())
+	span.SetAttr("http.request.method", r.Method)
+	span.SetAttr("http.route", r.Pattern)
+	span.SetAttr("url.path", r.URL.Path)
+	span.SetAttr("client.address", r.RemoteAddr)
+	span.SetAttr("user_agent.original", r.UserAgent())
+
+	return ctx, span
+}
+
+// endSpan records the response status of a request and ends its span
+func (a *App) endSpan(span *tracer.Span, v *Values) {
+	span.SetAttr("http.response.status_code", v.StatusCode)
+	if v.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(tracer.StatusError, http.StatusText(v.StatusCode))
+	}
+
+	span.End()
+}
+
+// EnableCORS answers preflight requests for every registered path
+// The CORS middleware itself must be included in the app middleware to set the response headers
+func (a *App) EnableCORS() {
+	a.cors = true
+
+	patterns := make([]string, 0, len(a.patterns))
+	for pattern := range a.patterns {
+		patterns = append(patterns, pattern)
+	}
+
+	for _, pattern := range patterns {
+		a.handlePreflight(pattern)
+	}
+}
+
+// register binds the handler to the mux and tracks the pattern for preflight handling and documentation
+func (a *App) register(pattern string, h http.HandlerFunc) *Route {
 	a.HandleFunc(pattern, h)
+	a.patterns[pattern] = struct{}{}
+
+	route := Route{Pattern: pattern}
+	a.routes = append(a.routes, &route)
+
+	if a.cors {
+		a.handlePreflight(pattern)
+	}
+
+	return &route
+}
+
+// handlePreflight registers an OPTIONS handler for the path of a pattern if one does not already exist
+func (a *App) handlePreflight(pattern string) {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return
+	}
+
+	if method == http.MethodOptions {
+		return
+	}
+
+	// Methods on the same path may name their wildcards differently so preflight is registered once per path shape
+	options := http.MethodOptions + " " + pathShape(strings.TrimSpace(path))
+	if _, exists := a.patterns[options]; exists {
+		return
+	}
+
+	preflight := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return Respond(ctx, w, nil, http.StatusNoContent)
+	}
+
+	a.Handle(options, preflight)
+}
+
+// pathShape renames the wildcards of a path by position so paths that only differ in wildcard names are equal
+// "/reviews/{id}/{rest...}" becomes "/reviews/{w0}/{w1...}" while the {$} anchor is kept
+func pathShape(path string) string {
+	var b strings.Builder
+	n := 0
+
+	for {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(path[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := path[start+1 : end]
+		b.WriteString(path[:start])
+
+		switch {
+		case name == "$":
+			b.WriteString("{$}")
+		case strings.HasSuffix(name, "..."):
+			fmt.Fprintf(&b, "{w%d...}", n)
+			n++
+		default:
+			fmt.Fprintf(&b, "{w%d}", n)
+			n++
+		}
+
+		path = path[end+1:]
+	}
+
+	b.WriteString(path)
+
+	return b.String()
 }
 
 // validateError checks if the error requires a system shutdown
@@ -0,0 +1,204 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketConfig represents how websocket connections are negotiated and kept alive
+type WebSocketConfig struct {
+	Subprotocols   []string
+	CheckOrigin    func(r *http.Request) bool
+	SendBuffer     int
+	MaxMessageSize int64
+	PingInterval   time.Duration
+	PongTimeout    time.Duration
+	WriteTimeout   time.Duration
+}
+
+// withDefaults fills in any unset values of the config
+func (cfg WebSocketConfig) withDefaults() WebSocketConfig {
+	if cfg.SendBuffer <= 0 {
+		cfg.SendBuffer = 64
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = 64 * 1024
+	}
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+	if cfg.PongTimeout <= cfg.PingInterval {
+		cfg.PongTimeout = cfg.PingInterval + 10*time.Second
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	return cfg
+}
+
+// WebSocket represents a connected websocket client
+// Outgoing messages are queued so one slow client cannot block the others
+type WebSocket struct {
+	conn      *websocket.Conn
+	cfg       WebSocketConfig
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	closeMsg  []byte
+}
+
+// WebSocketToken extracts a bearer token offered by a websocket client
+// Browsers cannot set headers on websocket requests so the token is sent as a "bearer.<token>"
+// subprotocol or as the access_token query parameter
+func WebSocketToken(r *http.Request) string {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if token, ok := strings.CutPrefix(protocol, "bearer."); ok {
+			return token
+		}
+	}
+
+	return r.URL.Query().Get("access_token")
+}
+
+// UpgradeWebSocket upgrades the request to a websocket connection
+// Failures are returned before anything is written so the error middleware can respond
+func UpgradeWebSocket(ctx context.Context, w http.ResponseWriter, r *http.Request, cfg WebSocketConfig) (*WebSocket, error) {
+	cfg = cfg.withDefaults()
+
+	upgrader := websocket.Upgrader{
+		Subprotocols: cfg.Subprotocols,
+		CheckOrigin:  cfg.CheckOrigin,
+		Error:        func(w http.ResponseWriter, r *http.Request, status int, reason error) {},
+	}
+
+	if !websocket.IsWebSocketUpgrade(r) {
+		return nil, errors.New("websocket upgrade required")
+	}
+
+	if upgrader.CheckOrigin != nil && !upgrader.CheckOrigin(r) {
+		return nil, errors.New("websocket origin not allowed")
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, fmt.Errorf("upgrading websocket: %w", err)
+	}
+
+	setStatusCode(ctx, http.StatusSwitchingProtocols)
+
+	conn.SetReadLimit(cfg.MaxMessageSize)
+
+	ws := WebSocket{
+		conn: conn,
+		cfg:  cfg,
+		send: make(chan []byte, cfg.SendBuffer),
+		done: make(chan struct{}),
+	}
+
+	return &ws, nil
+}
+
+// Send queues a message for the client without blocking
+// A client whose queue is full is too slow to keep up and is disconnected
+func (ws *WebSocket) Send(msg []byte) bool {
+	select {
+	case <-ws.done:
+		return false
+	default:
+	}
+
+	select {
+	case ws.send <- msg:
+		return true
+	default:
+		ws.Close(websocket.ClosePolicyViolation, "client too slow")
+		return false
+	}
+}
+
+// Close disconnects the client with a close code and reason
+func (ws *WebSocket) Close(code int, reason string) {
+	ws.closeOnce.Do(func() {
+		ws.closeMsg = websocket.FormatCloseMessage(code, reason)
+		close(ws.done)
+	})
+}
+
+// Run reads messages from the client until it disconnects, the context is cancelled,
+// or the app shuts down, while a separate goroutine writes queued messages and pings
+// The connection is hijacked so disconnects are not reported as errors to the caller
+func (ws *WebSocket) Run(ctx context.Context, onMessage func(msg []byte)) {
+	defer ws.conn.Close()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.Close(websocket.CloseGoingAway, "request cancelled")
+		case <-Closing(ctx):
+			ws.Close(websocket.CloseGoingAway, "server shutting down")
+		case <-ws.done:
+		}
+	}()
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		ws.writePump()
+	}()
+
+	ws.conn.SetReadDeadline(time.Now().Add(ws.cfg.PongTimeout))
+	ws.conn.SetPongHandler(func(string) error {
+		return ws.conn.SetReadDeadline(time.Now().Add(ws.cfg.PongTimeout))
+	})
+
+	for {
+		_, msg, err := ws.conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		onMessage(msg)
+	}
+
+	ws.Close(websocket.CloseNormalClosure, "")
+	<-writerDone
+}
+
+// writePump writes queued messages and pings until the connection is closed
+func (ws *WebSocket) writePump() {
+	ticker := time.NewTicker(ws.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-ws.send:
+			ws.conn.SetWriteDeadline(time.Now().Add(ws.cfg.WriteTimeout))
+			if err := ws.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				ws.Close(websocket.CloseAbnormalClosure, "")
+				ws.conn.Close()
+				return
+			}
+
+		case <-ticker.C:
+			ws.conn.SetWriteDeadline(time.Now().Add(ws.cfg.WriteTimeout))
+			if err := ws.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				ws.Close(websocket.CloseAbnormalClosure, "")
+				ws.conn.Close()
+				return
+			}
+
+		case <-ws.done:
+			ws.conn.WriteControl(websocket.CloseMessage, ws.closeMsg, time.Now().Add(ws.cfg.WriteTimeout))
+
+			// Unblock the reader if the client does not answer the close frame
+			ws.conn.SetReadDeadline(time.Now().Add(ws.cfg.WriteTimeout))
+			return
+		}
+	}
+}
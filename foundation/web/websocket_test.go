@@ -0,0 +1,94 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrew-hayworth22/critiquefy-service/foundation/web"
+	"github.com/gorilla/websocket"
+)
+
+func Test_WebSocketHub(t *testing.T) {
+	hub := web.NewHub()
+
+	app := web.NewApp(nil)
+	app.Handle("GET /rooms/{room}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if web.WebSocketToken(r) != "secret" {
+			return web.Respond(ctx, w, nil, http.StatusUnauthorized)
+		}
+
+		ws, err := web.UpgradeWebSocket(ctx, w, r, web.WebSocketConfig{Subprotocols: []string{"critiquefy.v1"}})
+		if err != nil {
+			return err
+		}
+
+		room := web.Param(r, "room")
+		hub.Join(room, ws)
+		defer hub.Leave(room, ws)
+
+		ws.Run(ctx, func(msg []byte) {
+			hub.Broadcast(room, msg)
+		})
+
+		return nil
+	})
+
+	srv := httptest.NewServer(app)
+	srv.Config.RegisterOnShutdown(app.CloseStreams)
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/rooms/review"
+	dialer := websocket.Dialer{Subprotocols: []string{"critiquefy.v1", "bearer.secret"}}
+
+	alice, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Should be able to connect with a subprotocol token: %s", err)
+	}
+	defer alice.Close()
+
+	if protocol := resp.Header.Get("Sec-WebSocket-Protocol"); protocol != "critiquefy.v1" {
+		t.Fatalf("Should negotiate the application subprotocol, got %q", protocol)
+	}
+
+	bob, _, err := websocket.DefaultDialer.Dial(url+"?access_token=secret", nil)
+	if err != nil {
+		t.Fatalf("Should be able to connect with a query token: %s", err)
+	}
+	defer bob.Close()
+
+	if _, _, err := websocket.DefaultDialer.Dial(url, nil); err == nil {
+		t.Fatalf("Should not be able to connect without a token")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for hub.Count("review") != 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := alice.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("Should be able to send a message: %s", err)
+	}
+
+	for name, conn := range map[string]*websocket.Conn{"alice": alice, "bob": bob} {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Should deliver the broadcast to %s: %s", name, err)
+		}
+		if string(msg) != "hello" {
+			t.Fatalf("Should deliver %q to %s, got %q", "hello", name, msg)
+		}
+	}
+
+	srv.Config.Shutdown(context.Background())
+
+	bob.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = bob.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("Should close clients when the server shuts down, got %v", err)
+	}
+}